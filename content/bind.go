@@ -0,0 +1,21 @@
+package content
+
+import (
+	"github.com/go-universal/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Bind ensures the request's Content-Type is "application/json" and parses the body into T.
+// It returns an HttpError with 406 status for invalid Content-Type and 422 status on parse failure.
+func Bind[T any](c *fiber.Ctx) (T, error) {
+	var v T
+	if !isValidContent(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		return v, http.NewStatusError(fiber.StatusNotAcceptable)
+	}
+
+	if err := c.BodyParser(&v); err != nil {
+		return v, http.NewWrappedError(err, fiber.StatusUnprocessableEntity)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,63 @@
+package content
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/inhies/go-bytesize"
+)
+
+// RequireBody is a middleware that ensures the request has a non-empty body on unsafe methods
+// (POST, PUT, PATCH and DELETE). Safe methods are passed through. Chunked requests without
+// Content-Length are checked by the raw body length. If the body is empty, it will execute
+// the optional onFail handler if provided, or return a 400 Bad Request status by default.
+func RequireBody(onFail ...fiber.Handler) fiber.Handler {
+	unsafe := []string{
+		fiber.MethodPost,
+		fiber.MethodPut,
+		fiber.MethodPatch,
+		fiber.MethodDelete,
+	}
+
+	return func(c *fiber.Ctx) error {
+		if slices.Contains(unsafe, c.Method()) &&
+			(c.Request().Header.ContentLength() == 0 || len(c.Request().Body()) == 0) {
+			if handler := first(onFail); handler != nil {
+				return handler(c)
+			}
+			return c.Status(fiber.StatusBadRequest).SendString("Bad Request")
+		}
+		return c.Next()
+	}
+}
+
+// MaxBodySize is a middleware that rejects requests with body larger than max size.
+// Requests are rejected by Content-Length, and chunked requests without Content-Length are
+// checked by the raw (not decompressed) body length. Use Decompress to limit decoded body size.
+// Fiber reads the whole body before handlers unless StreamRequestBody is enabled, so set fiber
+// BodyLimit config to bound the read itself. Use B, KB, MB, GB for size string.
+// If the body is too large, it will execute the optional onFail handler if provided,
+// or return a 413 Request Entity Too Large status by default.
+func MaxBodySize(max string, onFail ...fiber.Handler) fiber.Handler {
+	maxSize, parseErr := bytesize.Parse(max)
+
+	return func(c *fiber.Ctx) error {
+		if parseErr != nil {
+			return parseErr
+		}
+
+		length := c.Request().Header.ContentLength()
+		if int64(length) > int64(maxSize) || (length < 0 && int64(len(c.Request().Body())) > int64(maxSize)) {
+			if handler := first(onFail); handler != nil {
+				return handler(c)
+			}
+			return c.Status(fiber.StatusRequestEntityTooLarge).SendString("Request Entity Too Large")
+		}
+		return c.Next()
+	}
+}
+
+// LimitBodySize is an alias of MaxBodySize.
+func LimitBodySize(max string, onFail ...fiber.Handler) fiber.Handler {
+	return MaxBodySize(max, onFail...)
+}
@@ -0,0 +1,111 @@
+package content
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireBody(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequireBody())
+	app.All("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	tests := []struct {
+		method string
+		body   string
+		status int
+	}{
+		{fiber.MethodGet, "", fiber.StatusOK},
+		{fiber.MethodPost, "", fiber.StatusBadRequest},
+		{fiber.MethodPost, "data", fiber.StatusOK},
+		{fiber.MethodDelete, "", fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %q status = %d, want %d", tt.method, tt.body, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	app := fiber.New()
+	app.Use(MaxBodySize("10B"))
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{"small", fiber.StatusOK},
+		{"exactly10b", fiber.StatusOK},
+		{"larger than limit", fiber.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(tt.body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("body %q status = %d, want %d", tt.body, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestMaxBodySizeChunked(t *testing.T) {
+	app := fiber.New()
+	app.Use(MaxBodySize("100B"))
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	// Compressed body is small on wire but large after decoding
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(bytes.Repeat([]byte("a"), 10_000))
+	w.Close()
+
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		status   int
+	}{
+		{"small", []byte("small"), "", fiber.StatusOK},
+		{"large", bytes.Repeat([]byte("a"), 1000), "", fiber.StatusRequestEntityTooLarge},
+		{"compressed", buf.Bytes(), "gzip", fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		// Hide length to simulate chunked request
+		req := httptest.NewRequest(fiber.MethodPost, "/", io.MultiReader(bytes.NewReader(tt.body)))
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+		if tt.encoding != "" {
+			req.Header.Set("Content-Encoding", tt.encoding)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s status = %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+	}
+}
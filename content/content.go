@@ -0,0 +1,50 @@
+package content
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewMiddleware creates a middleware that ensures the request's Content-Type is one of the given types.
+// If validation fails, it will execute the fail handler if provided, or return a 406 Not Acceptable status by default.
+func NewMiddleware(types []string, options ...Option) fiber.Handler {
+	// Generate option
+	option := &option{
+		fail:   nil,
+		utf8:   false,
+		assume: false,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+
+	return func(c *fiber.Ctx) error {
+		contentType := c.Get(fiber.HeaderContentType)
+		if option.assume && len(types) > 0 && strings.TrimSpace(contentType) == "" {
+			contentType = types[0]
+			c.Request().Header.SetContentType(contentType)
+		}
+
+		if !isValidContent(contentType, types...) || (option.utf8 && !isUTF8(contentType)) {
+			if option.fail != nil {
+				return option.fail(c)
+			}
+			return c.Status(fiber.StatusNotAcceptable).SendString("Not Acceptable")
+		}
+		return c.Next()
+	}
+}
+
+// ContentOnly is a middleware that ensures the request's Content-Type is one of the given types.
+// If the Content-Type is none of these, it returns a 406 Not Acceptable status.
+func ContentOnly(types ...string) fiber.Handler {
+	return ContentOnlyWithFail(nil, types...)
+}
+
+// ContentOnlyWithFail is a middleware that ensures the request's Content-Type is one of the given types.
+// If the Content-Type is none of these, it will execute the onFail handler if not nil,
+// or return a 406 Not Acceptable status by default.
+func ContentOnlyWithFail(onFail fiber.Handler, types ...string) fiber.Handler {
+	return NewMiddleware(types, WithFail(onFail))
+}
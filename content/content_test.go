@@ -0,0 +1,104 @@
+package content
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// testContent sends post request with content type to middleware and returns status.
+func testContent(t *testing.T, handler fiber.Handler, contentType string) int {
+	t.Helper()
+
+	app := fiber.New()
+	app.Use(handler)
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.Get(fiber.HeaderContentType))
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", nil)
+	if contentType != "" {
+		req.Header.Set(fiber.HeaderContentType, contentType)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestContentOnly(t *testing.T) {
+	handler := ContentOnly("application/vnd.api+json")
+	tests := []struct {
+		contentType string
+		status      int
+	}{
+		{"application/vnd.api+json", fiber.StatusOK},
+		{"application/vnd.api+json; charset=utf-8", fiber.StatusOK},
+		{"application/json", fiber.StatusNotAcceptable},
+		{"", fiber.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		if status := testContent(t, handler, tt.contentType); status != tt.status {
+			t.Errorf("%q: status = %d, want %d", tt.contentType, status, tt.status)
+		}
+	}
+}
+
+func TestContentOnlyWithFail(t *testing.T) {
+	handler := ContentOnlyWithFail(func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusUnsupportedMediaType)
+	}, "application/vnd.api+json")
+
+	if status := testContent(t, handler, "text/plain"); status != fiber.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want 415", status)
+	}
+}
+
+func TestContentUTF8(t *testing.T) {
+	handler := NewMiddleware([]string{fiber.MIMEApplicationJSON}, WithUTF8())
+	tests := []struct {
+		contentType string
+		status      int
+	}{
+		{"application/json; charset=utf-8", fiber.StatusOK},
+		{"application/json; charset=UTF-8", fiber.StatusOK},
+		{"application/json", fiber.StatusOK},
+		{"application/json; charset=iso-8859-1", fiber.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		if status := testContent(t, handler, tt.contentType); status != tt.status {
+			t.Errorf("%q: status = %d, want %d", tt.contentType, status, tt.status)
+		}
+	}
+}
+
+func TestAssumeOnEmpty(t *testing.T) {
+	types := []string{fiber.MIMEApplicationJSON}
+	tests := []struct {
+		name        string
+		handler     fiber.Handler
+		contentType string
+		status      int
+	}{
+		{"default empty", NewMiddleware(types), "", fiber.StatusNotAcceptable},
+		{"json only empty", JsonOnly(), "", fiber.StatusNotAcceptable},
+		{"assume empty", NewMiddleware(types, WithAssumeOnEmpty()), "", fiber.StatusOK},
+		{"assume valid", NewMiddleware(types, WithAssumeOnEmpty()), fiber.MIMEApplicationJSON, fiber.StatusOK},
+		{"assume invalid", NewMiddleware(types, WithAssumeOnEmpty()), fiber.MIMETextPlain, fiber.StatusNotAcceptable},
+		{"json only assume empty", JsonOnly(WithAssumeOnEmpty()), "", fiber.StatusOK},
+		{"json only fail", JsonOnly(WithFail(func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusTeapot)
+		})), fiber.MIMETextPlain, fiber.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		if status := testContent(t, tt.handler, tt.contentType); status != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, status, tt.status)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package content
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/inhies/go-bytesize"
+)
+
+// RequireContentEncoding is a middleware that ensures the request body is encoded with
+// one of the given encodings (e.g. "gzip", "br"). Requests without body are passed through.
+// If the Content-Encoding is missing or not allowed, it returns a 415 Unsupported Media Type status.
+func RequireContentEncoding(encodings ...string) fiber.Handler {
+	return RequireContentEncodingWithFail(nil, encodings...)
+}
+
+// RequireContentEncodingWithFail is a middleware that ensures the request body is encoded with
+// one of the given encodings. If the Content-Encoding is missing or not allowed, it will execute
+// the onFail handler if not nil, or return a 415 Unsupported Media Type status by default.
+func RequireContentEncodingWithFail(onFail fiber.Handler, encodings ...string) fiber.Handler {
+	allowed := make([]string, 0, len(encodings))
+	for _, e := range encodings {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			allowed = append(allowed, e)
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		if len(c.Request().Body()) == 0 {
+			return c.Next()
+		}
+
+		applied := parseEncodings(c.Get(fiber.HeaderContentEncoding))
+		valid := len(applied) > 0
+		for _, e := range applied {
+			valid = valid && slices.Contains(allowed, e)
+		}
+
+		if !valid {
+			if onFail != nil {
+				return onFail(c)
+			}
+			return c.Status(fiber.StatusUnsupportedMediaType).SendString("Unsupported Media Type")
+		}
+		return c.Next()
+	}
+}
+
+// Decompress is a middleware that decodes gzip, deflate and br encoded request body up to max
+// decoded size and removes the Content-Encoding header before passing the request to the next handler.
+// Fiber c.Body() decodes Content-Encoding on every call without size limit, so decoded body is stored
+// once and later c.Body() calls return it as-is. Use B, KB, MB, GB for size string.
+// Requests without body are passed through. On unknown encoding, malformed body or decoded body
+// larger than max size, it will execute the optional onFail handler if provided, or return a
+// 415 Unsupported Media Type, 400 Bad Request or 413 Request Entity Too Large status by default.
+func Decompress(max string, onFail ...fiber.Handler) fiber.Handler {
+	maxSize, parseErr := bytesize.Parse(max)
+
+	fail := func(c *fiber.Ctx, status int) error {
+		if handler := first(onFail); handler != nil {
+			return handler(c)
+		}
+		return c.Status(status).SendString(utils.StatusMessage(status))
+	}
+
+	return func(c *fiber.Ctx) error {
+		if parseErr != nil {
+			return parseErr
+		}
+
+		applied := parseEncodings(c.Get(fiber.HeaderContentEncoding))
+		body := c.Request().Body()
+		if len(applied) == 0 || len(body) == 0 {
+			return c.Next()
+		}
+
+		// Chain decoders in reverse order of applied encodings
+		var r io.Reader = bytes.NewReader(body)
+		for _, e := range slices.Backward(applied) {
+			var err error
+			switch e {
+			case "identity":
+			case "gzip", "x-gzip":
+				r, err = gzip.NewReader(r)
+			case "deflate":
+				r, err = zlib.NewReader(r)
+			case "br":
+				r = brotli.NewReader(r)
+			default:
+				return fail(c, fiber.StatusUnsupportedMediaType)
+			}
+
+			if err != nil {
+				return fail(c, fiber.StatusBadRequest)
+			}
+		}
+
+		// Decode with limit
+		decoded, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+		if err != nil {
+			return fail(c, fiber.StatusBadRequest)
+		} else if int64(len(decoded)) > int64(maxSize) {
+			return fail(c, fiber.StatusRequestEntityTooLarge)
+		}
+
+		c.Request().SetBodyRaw(decoded)
+		c.Request().Header.Del(fiber.HeaderContentEncoding)
+		return c.Next()
+	}
+}
+
+// parseEncodings parses Content-Encoding header value into lowercase encodings list.
+func parseEncodings(header string) []string {
+	result := make([]string, 0)
+	for _, e := range strings.Split(header, ",") {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package content
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func gzipBody(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decompressApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/", Decompress("1KB"), func(c *fiber.Ctx) error {
+		return c.Send(c.Body())
+	})
+	return app
+}
+
+func TestDecompress(t *testing.T) {
+	req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(gzipBody(t, []byte("hello"))))
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err := decompressApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || string(body) != "hello" {
+		t.Errorf("got %d %q, want 200 \"hello\"", resp.StatusCode, body)
+	}
+}
+
+func TestDecompressBomb(t *testing.T) {
+	bomb := gzipBody(t, []byte(strings.Repeat("a", 1<<20)))
+	req := httptest.NewRequest(fiber.MethodPost, "/", bytes.NewReader(bomb))
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err := decompressApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+}
+
+func TestDecompressUnknownEncoding(t *testing.T) {
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("data"))
+	req.Header.Set(fiber.HeaderContentEncoding, "zstd")
+	resp, err := decompressApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want 415", resp.StatusCode)
+	}
+}
+
+func TestDecompressMalformed(t *testing.T) {
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("not gzip"))
+	req.Header.Set(fiber.HeaderContentEncoding, "gzip")
+	resp, err := decompressApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestRequireContentEncoding(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", RequireContentEncoding("gzip"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		encoding string
+		status   int
+	}{
+		{"allowed", "data", "gzip", fiber.StatusOK},
+		{"missing", "data", "", fiber.StatusUnsupportedMediaType},
+		{"not allowed", "data", "br", fiber.StatusUnsupportedMediaType},
+		{"empty body", "", "", fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set(fiber.HeaderContentEncoding, tt.encoding)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -3,16 +3,9 @@ package content
 import "github.com/gofiber/fiber/v2"
 
 // JsonOnly is a middleware that ensures the request's Content-Type is "application/json".
-// If the Content-Type is not "application/json", it will execute the optional onFail handler
+// If the Content-Type is not "application/json", it will execute the WithFail handler
 // if provided, or return a 406 Not Acceptable status by default.
-func JsonOnly(onFail ...fiber.Handler) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		if !isValidContent(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
-			if len(onFail) > 0 && onFail[0] != nil {
-				return onFail[0](c)
-			}
-			return c.Status(fiber.StatusNotAcceptable).SendString("Not Acceptable")
-		}
-		return c.Next()
-	}
+// Use WithAssumeOnEmpty option to accept requests without Content-Type.
+func JsonOnly(options ...Option) fiber.Handler {
+	return NewMiddleware([]string{fiber.MIMEApplicationJSON}, options...)
 }
@@ -0,0 +1,35 @@
+package content
+
+import "github.com/gofiber/fiber/v2"
+
+// option holds the configuration options for content middleware.
+type option struct {
+	fail   fiber.Handler
+	utf8   bool
+	assume bool
+}
+
+// Option defines a function type for configuring content Option.
+type Option func(*option)
+
+// WithFail sets a custom failure handler for content validation.
+func WithFail(handler fiber.Handler) Option {
+	return func(o *option) {
+		o.fail = handler
+	}
+}
+
+// WithUTF8 requires the request's charset to be utf-8 or not specified.
+func WithUTF8() Option {
+	return func(o *option) {
+		o.utf8 = true
+	}
+}
+
+// WithAssumeOnEmpty accepts requests with empty or missing Content-Type for legacy clients.
+// Request Content-Type is set to the first allowed type, so body parsers work as expected.
+func WithAssumeOnEmpty() Option {
+	return func(o *option) {
+		o.assume = true
+	}
+}
@@ -0,0 +1,39 @@
+package content
+
+import (
+	"errors"
+
+	"github.com/go-universal/http"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// JSON sends v as JSON response with the given status code.
+func JSON(c *fiber.Ctx, status int, v any) error {
+	return c.Status(status).JSON(v, fiber.MIMEApplicationJSONCharsetUTF8)
+}
+
+// Problem sends err as RFC 7807 application/problem+json response.
+// Status and detail are taken from HttpError or fiber.Error, other errors are sent as 500.
+func Problem(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	detail := utils.StatusMessage(status)
+
+	var he http.HttpError
+	var fe *fiber.Error
+	if errors.As(err, &he) {
+		status = he.Status
+		detail = he.Message
+	} else if errors.As(err, &fe) {
+		status = fe.Code
+		detail = fe.Message
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"type":     "about:blank",
+		"title":    utils.StatusMessage(status),
+		"status":   status,
+		"detail":   detail,
+		"instance": c.OriginalURL(),
+	}, "application/problem+json")
+}
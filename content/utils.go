@@ -1,6 +1,11 @@
 package content
 
-import "strings"
+import (
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 // isValidContent checks if the given string `c` starts with any of the valid prefixes provided in `valids`.
 // The comparison is case-insensitive and trims any leading or trailing spaces from `c`.
@@ -18,3 +23,22 @@ func isValidContent(c string, valids ...string) bool {
 	// Return false if no valid prefix matches.
 	return false
 }
+
+// isUTF8 checks if the media type `c` has utf-8 charset or no charset parameter.
+func isUTF8(c string) bool {
+	_, params, err := mime.ParseMediaType(c)
+	if err != nil {
+		return false
+	}
+
+	charset := strings.ToLower(strings.TrimSpace(params["charset"]))
+	return charset == "" || charset == "utf-8" || charset == "utf8"
+}
+
+// first returns the first handler or nil if not provided.
+func first(handlers []fiber.Handler) fiber.Handler {
+	if len(handlers) > 0 {
+		return handlers[0]
+	}
+	return nil
+}
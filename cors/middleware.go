@@ -0,0 +1,116 @@
+package cors
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewMiddleware creates a new CORS middleware with the provided options.
+// It answers preflight OPTIONS requests with 204 No Content and sets CORS headers for allowed origins.
+// By default all origins and common methods are allowed.
+// It panics if credentials are allowed for "*" origin.
+//
+// This middleware should be registered before session and CSRF middlewares.
+func NewMiddleware(options ...Option) fiber.Handler {
+	// Generate option
+	option := &option{
+		origins: []string{"*"},
+		methods: []string{
+			fiber.MethodGet,
+			fiber.MethodPost,
+			fiber.MethodHead,
+			fiber.MethodPut,
+			fiber.MethodDelete,
+			fiber.MethodPatch,
+		},
+		headers:     nil,
+		expose:      nil,
+		session:     nil,
+		credentials: false,
+		maxAge:      0,
+		next:        nil,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+
+	// Validate credentials with any origin
+	if option.credentials && slices.Contains(option.origins, "*") {
+		panic("[CORS] insecure setup, credentials can not be allowed for \"*\" origin")
+	}
+
+	return func(c *fiber.Ctx) error {
+		// Skip
+		if option.next != nil && option.next(c) {
+			return c.Next()
+		}
+
+		// Validate origin
+		origin := c.Get(fiber.HeaderOrigin)
+		c.Vary(fiber.HeaderOrigin)
+		preflight := c.Method() == fiber.MethodOptions &&
+			c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+		if origin == "" || !isAllowedOrigin(origin, option.origins) {
+			if preflight {
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+			return c.Next()
+		}
+
+		// Set origin and credentials
+		if option.credentials {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+			c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+		} else if len(option.origins) == 1 && option.origins[0] == "*" {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+		} else {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+		}
+
+		// Preflight
+		if preflight {
+			c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+			c.Set(fiber.HeaderAccessControlAllowMethods, strings.Join(option.methods, ", "))
+			if len(option.headers) > 0 {
+				headers := append(slices.Clone(option.headers), option.session...)
+				c.Set(fiber.HeaderAccessControlAllowHeaders, strings.Join(headers, ", "))
+			} else if requested := c.Get(fiber.HeaderAccessControlRequestHeaders); requested != "" {
+				c.Set(fiber.HeaderAccessControlAllowHeaders, requested)
+			}
+			if option.maxAge > 0 {
+				c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(int(option.maxAge.Seconds())))
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+
+		// Expose headers, next middlewares append their own headers
+		for _, h := range append(slices.Clone(option.expose), option.session...) {
+			c.Append(fiber.HeaderAccessControlExposeHeaders, h)
+		}
+
+		return c.Next()
+	}
+}
+
+// isAllowedOrigin checks if origin matches any of allowed origin patterns.
+func isAllowedOrigin(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+
+		if prefix, suffix, ok := strings.Cut(pattern, "*"); ok &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) &&
+			strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+package cors
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newApp(options ...Option) *fiber.App {
+	app := fiber.New()
+	app.Use(NewMiddleware(options...))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+func TestPreflight(t *testing.T) {
+	app := newApp(
+		WithOrigins("https://*.example.com"),
+		WithHeaders("Content-Type"),
+		WithSessionHeaders("X-Session", "X-CSRF-TOKEN"),
+	)
+
+	req := httptest.NewRequest(fiber.MethodOptions, "/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != "https://app.example.com" {
+		t.Errorf("allow origin = %q", got)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowHeaders); got != "Content-Type, X-Session, X-CSRF-TOKEN" {
+		t.Errorf("allow headers = %q", got)
+	}
+}
+
+func TestPreflightDisallowedOrigin(t *testing.T) {
+	app := newApp(WithOrigins("https://*.example.com"))
+
+	req := httptest.NewRequest(fiber.MethodOptions, "/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://example.com.evil.com")
+	req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != "" {
+		t.Errorf("allow origin = %q, want empty", got)
+	}
+}
+
+func TestCredentialedGet(t *testing.T) {
+	app := newApp(
+		WithOrigins("https://app.example.com"),
+		WithCredentials(),
+		WithSessionHeaders("X-Session"),
+	)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != "https://app.example.com" {
+		t.Errorf("allow origin = %q", got)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowCredentials); got != "true" {
+		t.Errorf("allow credentials = %q", got)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlExposeHeaders); got != "X-Session" {
+		t.Errorf("expose headers = %q", got)
+	}
+
+	// Other origins are not reflected
+	req = httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://evil.com")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowCredentials); got != "" {
+		t.Errorf("allow credentials = %q, want empty", got)
+	}
+}
+
+func TestCredentialsWithAnyOrigin(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for credentials with \"*\" origin")
+		}
+	}()
+
+	NewMiddleware(WithCredentials())
+}
@@ -0,0 +1,103 @@
+package cors
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// option holds the configuration options for CORS middleware.
+type option struct {
+	origins     []string
+	methods     []string
+	headers     []string
+	expose      []string
+	session     []string
+	credentials bool
+	maxAge      time.Duration
+	next        func(*fiber.Ctx) bool
+}
+
+// Option defines a function type for configuring CORS Option.
+type Option func(*option)
+
+// WithOrigins sets the allowed origins. Supports "*" for any origin
+// and wildcard subdomains (e.g. "https://*.example.com").
+func WithOrigins(origins ...string) Option {
+	return func(o *option) {
+		o.origins = normalize(origins, false)
+	}
+}
+
+// WithMethods sets the allowed methods for preflight requests.
+func WithMethods(methods ...string) Option {
+	return func(o *option) {
+		o.methods = normalize(methods, true)
+	}
+}
+
+// WithHeaders sets the allowed request headers for preflight requests.
+// If no header set, requested headers are reflected so header based session and CSRF work out of the box.
+func WithHeaders(headers ...string) Option {
+	return func(o *option) {
+		o.headers = normalize(headers, false)
+	}
+}
+
+// WithSessionHeaders sets the header names used by header based session and CSRF middlewares
+// (e.g. session.WithHeader and csrf.WithHeader names). Names are always allowed in preflight
+// requests (merged with WithHeaders list) and exposed to client, since preflight requests
+// are answered before session and CSRF middlewares run.
+func WithSessionHeaders(names ...string) Option {
+	return func(o *option) {
+		o.session = normalize(names, false)
+	}
+}
+
+// WithExposeHeaders sets the response headers exposed to client.
+// Headers exposed by session, CSRF and limiter middlewares are kept.
+func WithExposeHeaders(headers ...string) Option {
+	return func(o *option) {
+		o.expose = normalize(headers, false)
+	}
+}
+
+// WithCredentials allows credentials (cookies, authorization headers) in cross-origin requests.
+// Request origin is reflected instead of "*" when credentials allowed.
+// Credentials can not be combined with "*" origin, NewMiddleware panics on this insecure setup.
+func WithCredentials() Option {
+	return func(o *option) {
+		o.credentials = true
+	}
+}
+
+// WithMaxAge sets how long preflight results can be cached.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(o *option) {
+		if maxAge > 0 {
+			o.maxAge = maxAge
+		}
+	}
+}
+
+// WithNext sets a custom function to skip CORS for certain requests.
+func WithNext(handler func(*fiber.Ctx) bool) Option {
+	return func(o *option) {
+		o.next = handler
+	}
+}
+
+// normalize trims and removes empty values.
+func normalize(values []string, upper bool) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			if upper {
+				v = strings.ToUpper(v)
+			}
+			result = append(result, v)
+		}
+	}
+	return result
+}
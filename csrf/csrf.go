@@ -1,8 +1,12 @@
 package csrf
 
 import (
+	"encoding/json"
 	"errors"
+	"path"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-universal/http/session"
 	"github.com/gofiber/fiber/v2"
@@ -19,6 +23,23 @@ func GetToken(c *fiber.Ctx) string {
 	return s.Cast("csrf").StringSafe("")
 }
 
+// EnsureToken retrieves the token from the session or generates a new one if not exists.
+// Use it with WithLazy option to generate token when rendering forms.
+// It returns an error if the session cannot be resolved.
+func EnsureToken(c *fiber.Ctx) (string, error) {
+	// Parse session
+	s := session.Parse(c)
+	if s == nil {
+		return "", errors.New("failed to resolve session")
+	}
+
+	// Get or generate token
+	if token := s.Cast("csrf").StringSafe(""); token != "" {
+		return token, nil
+	}
+	return refresh(s)
+}
+
 // RefreshToken generates a new CSRF token and saves it to the session.
 // It returns the generated token or an error if the session cannot be resolved.
 func RefreshToken(c *fiber.Ctx) (string, error) {
@@ -29,14 +50,36 @@ func RefreshToken(c *fiber.Ctx) (string, error) {
 	}
 
 	// Save to session
-	return refresh(s), nil
+	return refresh(s)
+}
+
+// SessionKeys returns the session data keys used to store CSRF token.
+// Pass them to session.WithPreservedKeys to keep token valid on session Clear.
+func SessionKeys() []string {
+	return []string{"csrf", "csrf_at"}
 }
 
-// refresh csrf on session
-func refresh(s session.Session) string {
+// refresh csrf on session.
+// Returns error if token not stored on session (e.g. not-exists readonly session).
+func refresh(s session.Session) (string, error) {
 	token := uuid.NewString()
 	s.Set("csrf", token)
-	return token
+	s.Set("csrf_at", time.Now().Format(time.RFC3339))
+	if s.Cast("csrf").StringSafe("") != token {
+		return "", errors.New("failed to store csrf token on read-only session")
+	}
+	return token, nil
+}
+
+// isExpired checks if session csrf token generated before ttl.
+// Token without generation time is expired.
+func isExpired(s session.Session, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+
+	at, err := time.Parse(time.RFC3339, s.Cast("csrf_at").StringSafe(""))
+	return err != nil || time.Since(at) > ttl
 }
 
 // isRFC9110Method check if request method not GET, HEAD, OPTIONS and TRACE.
@@ -53,6 +96,37 @@ func isRFC9110Method(c *fiber.Ctx) bool {
 	)
 }
 
+// isExempt checks if request path matches any of exempt patterns.
+func isExempt(c *fiber.Ctx, patterns []string) bool {
+	trim := func(p string) string {
+		if len(p) > 1 {
+			return strings.TrimRight(p, "/")
+		}
+		return p
+	}
+
+	p := trim(c.Path())
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && !strings.ContainsAny(prefix, "*?[") {
+			// Prefix pattern
+			prefix = trim(prefix)
+			if p == prefix || strings.HasPrefix(p, strings.TrimRight(prefix, "/")+"/") {
+				return true
+			}
+		} else if strings.ContainsAny(pattern, "*?[") {
+			// Glob pattern
+			if ok, _ := path.Match(trim(pattern), p); ok {
+				return true
+			}
+		} else if trim(pattern) == p {
+			// Exact pattern
+			return true
+		}
+	}
+
+	return false
+}
+
 // getBodyValue get value from request body.
 func getBodyValue(ctx *fiber.Ctx, key string) string {
 	var body map[string]interface{}
@@ -63,3 +137,23 @@ func getBodyValue(ctx *fiber.Ctx, key string) string {
 	value, _ := body[key].(string)
 	return value
 }
+
+// getJSONValue get value from JSON request body by dot separated path.
+// Request body is not consumed.
+func getJSONValue(ctx *fiber.Ctx, path string) string {
+	var body any
+	if err := json.Unmarshal(ctx.Body(), &body); err != nil {
+		return ""
+	}
+
+	for _, k := range strings.Split(path, ".") {
+		m, ok := body.(map[string]any)
+		if !ok {
+			return ""
+		}
+		body = m[k]
+	}
+
+	value, _ := body.(string)
+	return value
+}
@@ -12,22 +12,35 @@ import (
 // It validates the CSRF token for incoming requests and generates a new token if needed.
 // By default, this middleware generates a 419 HTTP response if CSRF validation fails.
 //
-// This middleware must be called after the session middleware.
+// This middleware must be called after the session middleware. Generated token is persisted by
+// session middleware Save after the request is processed (header and cookie based sessions alike),
+// so handler errors drop the generated token unless session WithSaveOnError option is used.
+// Token can not be stored on not-exists read-only session, so request fails with token generation error.
 func NewMiddleware(options ...Option) fiber.Handler {
 	// Generate option
 	option := &option{
-		header: false,
-		key:    "csrf_token",
-		fail:   nil,
-		next:   nil,
+		header:  false,
+		angular: false,
+		json:    false,
+		key:     "csrf_token",
+		fail:    nil,
+		next:    nil,
+		exempt:  nil,
+		lazy:    false,
+		ttl:     0,
+		expose:  "",
+		trust:   false,
 	}
 	for _, opt := range options {
 		opt(option)
 	}
 
+	// Client obtains token from safe requests in angular and response header modes
+	lazy := option.lazy && !option.angular && option.expose == ""
+
 	return func(c *fiber.Ctx) error {
 		// Skip
-		if option.next != nil && option.next(c) {
+		if (option.next != nil && option.next(c)) || isExempt(c, option.exempt) {
 			return c.Next()
 		}
 
@@ -38,8 +51,34 @@ func NewMiddleware(options ...Option) fiber.Handler {
 		}
 
 		token := session.Cast("csrf").StringSafe("")
-		if token == "" { // Generate or refresh token if needed
-			token = refresh(session)
+		if (token == "" && (!lazy || isRFC9110Method(c))) || // Generate token if needed
+			(token != "" && isExpired(session, option.ttl)) { // Refresh expired token
+			var err error
+			if token, err = refresh(session); err != nil {
+				return err
+			}
+		}
+
+		// Send token cookie for angular
+		if option.angular && token != "" && c.Cookies("XSRF-TOKEN") != token {
+			c.Cookie(&fiber.Cookie{
+				Name:     "XSRF-TOKEN",
+				Value:    token,
+				Path:     "/",
+				SameSite: fiber.CookieSameSiteStrictMode,
+				HTTPOnly: false,
+			})
+		}
+
+		// Send token header
+		if option.expose != "" && token != "" && !isRFC9110Method(c) {
+			c.Append("Access-Control-Expose-Headers", option.expose)
+			c.Set(option.expose, token)
+		}
+
+		// Trust same origin request
+		if option.trust && strings.EqualFold(c.Get("Sec-Fetch-Site"), "same-origin") {
+			return c.Next()
 		}
 
 		// Proccess request
@@ -57,7 +96,12 @@ func NewMiddleware(options ...Option) fiber.Handler {
 			}
 		} else {
 			if isRFC9110Method(c) {
-				input := getBodyValue(c, option.key)
+				var input string
+				if option.json {
+					input = getJSONValue(c, option.key)
+				} else {
+					input = getBodyValue(c, option.key)
+				}
 				if token == "" || input != token {
 					if option.fail != nil {
 						return option.fail(c)
@@ -0,0 +1,225 @@
+package csrf
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/go-universal/http/session"
+	"github.com/gofiber/fiber/v2"
+)
+
+func newApp(sessionOptions []session.Option, options ...Option) *fiber.App {
+	app := fiber.New()
+	app.Use(session.NewMiddleware(testcache.New(), sessionOptions...))
+	app.Use(NewMiddleware(options...))
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, method string, headers map[string]string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestHeaderSessionRoundTrip(t *testing.T) {
+	app := newApp(
+		[]session.Option{session.WithHeader("X-Session")},
+		WithHeader("X-CSRF-TOKEN"),
+		WithResponseHeader("X-CSRF"),
+	)
+
+	// Token generated on safe request
+	resp := do(t, app, fiber.MethodGet, nil)
+	id := resp.Header.Get("X-Session")
+	token := resp.Header.Get("X-CSRF")
+	if id == "" || token == "" {
+		t.Fatalf("session = %q, token = %q, want non-empty", id, token)
+	}
+
+	// Token survives to next request
+	resp = do(t, app, fiber.MethodPost, map[string]string{"X-Session": id, "X-CSRF-TOKEN": token})
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("valid token status = %d, want 200", resp.StatusCode)
+	}
+
+	resp = do(t, app, fiber.MethodPost, map[string]string{"X-Session": id, "X-CSRF-TOKEN": "invalid"})
+	if resp.StatusCode != 419 {
+		t.Errorf("invalid token status = %d, want 419", resp.StatusCode)
+	}
+}
+
+func TestReadonlySession(t *testing.T) {
+	app := newApp(
+		[]session.Option{session.WithHeader("X-Session"), session.WithReadonly()},
+		WithHeader("X-CSRF-TOKEN"),
+	)
+
+	// Token can not be stored on not-exists session
+	for _, method := range []string{fiber.MethodGet, fiber.MethodPost} {
+		if resp := do(t, app, method, nil); resp.StatusCode != fiber.StatusInternalServerError {
+			t.Errorf("%s status = %d, want 500", method, resp.StatusCode)
+		}
+	}
+}
+
+func TestSameSiteTrust(t *testing.T) {
+	app := newApp(
+		[]session.Option{session.WithHeader("X-Session")},
+		WithHeader("X-CSRF-TOKEN"),
+		WithSameSiteTrust(),
+	)
+
+	tests := []struct {
+		site   string
+		status int
+	}{
+		{"same-origin", fiber.StatusOK},
+		{"same-site", 419},
+		{"cross-site", 419},
+		{"", 419},
+	}
+
+	for _, tt := range tests {
+		headers := map[string]string{}
+		if tt.site != "" {
+			headers["Sec-Fetch-Site"] = tt.site
+		}
+
+		if resp := do(t, app, fiber.MethodPost, headers); resp.StatusCode != tt.status {
+			t.Errorf("Sec-Fetch-Site %q status = %d, want %d", tt.site, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestIsExempt(t *testing.T) {
+	patterns := []string{"/webhook", "/hooks/*", "/api/*/callback"}
+	tests := []struct {
+		path   string
+		exempt bool
+	}{
+		{"/webhook", true},
+		{"/webhook/", true},
+		{"/webhooks", false},
+		{"/hooks", true},
+		{"/hooks/github", true},
+		{"/hooks/github/push", true},
+		{"/hooksx", false},
+		{"/api/v1/callback", true},
+		{"/api/v1/v2/callback", false},
+		{"/", false},
+	}
+
+	app := fiber.New()
+	for _, tt := range tests {
+		app.Get(tt.path, func(c *fiber.Ctx) error {
+			if got := isExempt(c, patterns); got != tt.exempt {
+				t.Errorf("isExempt(%q) = %v, want %v", tt.path, got, tt.exempt)
+			}
+			return nil
+		})
+
+		if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTokenTTL(t *testing.T) {
+	store := testcache.New()
+	app := fiber.New()
+	app.Use(session.NewMiddleware(store, session.WithHeader("X-Session")))
+	app.Use(NewMiddleware(WithHeader("X-CSRF-TOKEN"), WithResponseHeader("X-CSRF"), WithTokenTTL(time.Hour)))
+	app.All("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp := do(t, app, fiber.MethodGet, nil)
+	id, token := resp.Header.Get("X-Session"), resp.Header.Get("X-CSRF")
+
+	// Valid token
+	headers := map[string]string{"X-Session": id, "X-CSRF-TOKEN": token}
+	if resp := do(t, app, fiber.MethodPost, headers); resp.StatusCode != fiber.StatusOK {
+		t.Errorf("valid token status = %d, want 200", resp.StatusCode)
+	}
+
+	// Expired token on unsafe request
+	var data map[string]any
+	stored, _ := store.Get("ses-" + id)
+	json.Unmarshal(stored.([]byte), &data)
+	data["csrf_at"] = time.Now().Add(-2 * time.Hour).Format(time.RFC3339)
+	encoded, _ := json.Marshal(data)
+	ttl := time.Hour
+	store.Put("ses-"+id, encoded, &ttl)
+
+	if resp := do(t, app, fiber.MethodPost, headers); resp.StatusCode != 419 {
+		t.Errorf("expired token status = %d, want 419", resp.StatusCode)
+	}
+
+	// Refreshed token on safe request
+	resp = do(t, app, fiber.MethodGet, map[string]string{"X-Session": id})
+	refreshed := resp.Header.Get("X-CSRF")
+	if refreshed == "" || refreshed == token {
+		t.Fatalf("refreshed token = %q, want new token", refreshed)
+	}
+
+	headers["X-CSRF-TOKEN"] = refreshed
+	if resp := do(t, app, fiber.MethodPost, headers); resp.StatusCode != fiber.StatusOK {
+		t.Errorf("refreshed token status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestLazy(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		token   func(resp *http.Response) string
+	}{
+		{"lazy", []Option{WithLazy()}, nil},
+		{"response header", []Option{WithLazy(), WithHeader("X-CSRF-TOKEN"), WithResponseHeader("X-CSRF")}, func(resp *http.Response) string {
+			return resp.Header.Get("X-CSRF")
+		}},
+		{"angular", []Option{WithLazy(), WithAngular()}, func(resp *http.Response) string {
+			for _, c := range resp.Cookies() {
+				if c.Name == "XSRF-TOKEN" {
+					return c.Value
+				}
+			}
+			return ""
+		}},
+	}
+
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Use(session.NewMiddleware(testcache.New(), session.WithHeader("X-Session")))
+		app.Use(NewMiddleware(tt.options...))
+		app.Get("/", func(c *fiber.Ctx) error {
+			return c.SendString(GetToken(c))
+		})
+
+		resp := do(t, app, fiber.MethodGet, nil)
+		body, _ := io.ReadAll(resp.Body)
+		if tt.token == nil {
+			if len(body) != 0 {
+				t.Errorf("%s: token generated on safe request", tt.name)
+			}
+		} else if token := tt.token(resp); token == "" || token != string(body) {
+			t.Errorf("%s: sent token = %q, want %q", tt.name, token, body)
+		}
+	}
+}
@@ -1,13 +1,25 @@
 package csrf
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 // option holds the configuration options for CSRF middleware.
 type option struct {
-	header bool
-	key    string
-	fail   fiber.Handler
-	next   func(*fiber.Ctx) bool
+	header  bool
+	angular bool
+	json    bool
+	key     string
+	fail    fiber.Handler
+	next    func(*fiber.Ctx) bool
+	exempt  []string
+	lazy    bool
+	ttl     time.Duration
+	expose  string
+	trust   bool
 }
 
 // Option defines a function type for configuring CSRF Option.
@@ -27,22 +39,104 @@ func WithNext(handler func(*fiber.Ctx) bool) Option {
 	}
 }
 
+// WithExempt sets paths to skip CSRF validation for.
+// Paths are matched case-sensitively and trailing slash is ignored.
+// Supports exact path ("/webhook"), prefix ("/webhooks/*") and glob ("/hooks/*/github") patterns.
+func WithExempt(paths ...string) Option {
+	return func(o *option) {
+		for _, p := range paths {
+			if p = strings.TrimSpace(p); p != "" {
+				o.exempt = append(o.exempt, p)
+			}
+		}
+	}
+}
+
+// WithLazy configures the CSRF middleware to not generate token on safe (GET, HEAD, OPTIONS, TRACE) requests.
+// Use EnsureToken to generate token when rendering forms. This prevents needless session writes for
+// anonymous visitors and crawlers. Ignored with WithAngular and WithResponseHeader options,
+// since clients obtain token from safe requests in these modes.
+func WithLazy() Option {
+	return func(o *option) {
+		o.lazy = true
+	}
+}
+
+// WithTokenTTL sets the CSRF token lifetime independent from session lifetime.
+// Expired token is regenerated on next request and unsafe requests presenting it are rejected.
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(o *option) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithResponseHeader configures the CSRF middleware to send the token in the given response header
+// (e.g. X-CSRF-Token) on safe requests. Header is exposed to cross-origin clients.
+func WithResponseHeader(name string) Option {
+	return func(o *option) {
+		o.expose = strings.TrimSpace(name)
+	}
+}
+
+// WithSameSiteTrust configures the CSRF middleware to skip token validation for same-origin requests
+// detected by "Sec-Fetch-Site: same-origin" header. Requests without header are validated strictly.
+func WithSameSiteTrust() Option {
+	return func(o *option) {
+		o.trust = true
+	}
+}
+
 // WithHeader configures the CSRF middleware to check CSRF token from header.
+// WithHeader, WithForm, WithAngular and WithJSONField are mutually exclusive and the last one applied wins.
 func WithHeader(name string) Option {
 	return func(o *option) {
 		if name != "" {
 			o.header = true
+			o.angular = false
+			o.json = false
 			o.key = name
 		}
 	}
 }
 
 // WithForm configures the CSRF middleware to check CSRF token from form field.
+// WithHeader, WithForm, WithAngular and WithJSONField are mutually exclusive and the last one applied wins.
 func WithForm(name string) Option {
 	return func(o *option) {
 		if name != "" {
 			o.header = false
+			o.angular = false
+			o.json = false
 			o.key = name
 		}
 	}
 }
+
+// WithAngular configures the CSRF middleware to be compatible with Angular HttpClient.
+// Token is sent in non-HTTPOnly XSRF-TOKEN cookie and checked from X-XSRF-TOKEN header.
+// WithHeader, WithForm, WithAngular and WithJSONField are mutually exclusive and the last one applied wins.
+func WithAngular() Option {
+	return func(o *option) {
+		o.header = true
+		o.angular = true
+		o.json = false
+		o.key = "X-XSRF-TOKEN"
+	}
+}
+
+// WithJSONField configures the CSRF middleware to check CSRF token from JSON body field.
+// Nested fields are addressed by dot separated path (e.g. "meta.csrf").
+// Malformed JSON body fails validation.
+// WithHeader, WithForm, WithAngular and WithJSONField are mutually exclusive and the last one applied wins.
+func WithJSONField(path string) Option {
+	return func(o *option) {
+		if path = strings.TrimSpace(path); path != "" {
+			o.header = false
+			o.angular = false
+			o.json = true
+			o.key = path
+		}
+	}
+}
@@ -1,22 +1,30 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
 	"mime/multipart"
 	"runtime"
+	"slices"
+	"strings"
 
-	"github.com/gabriel-vasile/mimetype"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/inhies/go-bytesize"
 )
 
 // HttpError represents an HTTP error with additional context.
 type HttpError struct {
-	Line    int            // Line number where the error occurred.
-	File    string         // File name where the error occurred.
-	Body    map[string]any // Request body data (if available).
-	Status  int            // HTTP status code.
-	Message string         // Error message.
+	Line    int               // Line number where the error occurred.
+	File    string            // File name where the error occurred.
+	Body    map[string]any    // Request body data (if available).
+	Extra   map[string]any    // Extra context fields (e.g. tenant id, trace id).
+	Stack   []string          // Call stack frames (if captured).
+	Cause   error             // Underlying error (if wrapped).
+	Fields  map[string]string // Field validation errors (if any).
+	Status  int               // HTTP status code.
+	Message string            // Error message.
+	debug   bool              // Include internal fields in JSON.
 }
 
 // Error returns the error message as a string.
@@ -24,7 +32,66 @@ func (he HttpError) Error() string {
 	return he.Message
 }
 
+// Unwrap returns the underlying error.
+func (he HttpError) Unwrap() error {
+	return he.Cause
+}
+
+// WithField returns a copy of the error with an extra context field attached.
+func (he HttpError) WithField(key string, value any) HttpError {
+	extra := make(map[string]any, len(he.Extra)+1)
+	for k, v := range he.Extra {
+		extra[k] = v
+	}
+	extra[key] = value
+
+	he.Extra = extra
+	return he
+}
+
+// Debug returns a copy of the error that includes internal fields (file, line, body, extra, stack and cause) in JSON.
+func (he HttpError) Debug() HttpError {
+	he.debug = true
+	return he
+}
+
+// MarshalJSON encodes status, message, code (from "code" extra field) and validation fields of the error.
+// Internal fields are included only for errors returned by Debug.
+func (he HttpError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(he.toMap())
+}
+
+// toMap returns the JSON representation fields of the error.
+func (he HttpError) toMap() map[string]any {
+	result := map[string]any{
+		"status":  he.Status,
+		"message": he.Message,
+	}
+
+	if code, ok := he.Extra["code"]; ok {
+		result["code"] = code
+	}
+
+	if len(he.Fields) > 0 {
+		result["fields"] = he.Fields
+	}
+
+	if he.debug {
+		result["file"] = he.File
+		result["line"] = he.Line
+		result["body"] = he.Body
+		result["extra"] = he.Extra
+		result["stack"] = he.Stack
+		if he.Cause != nil {
+			result["cause"] = he.Cause.Error()
+		}
+	}
+
+	return result
+}
+
 // NewError creates an HttpError with a message and optional status code.
+// Redirect (3xx) and error (4xx, 5xx) codes are accepted.
 // Defaults to status 500 if none is provided.
 func NewError(e string, status ...int) error {
 	file, line, _ := realCaller()
@@ -37,6 +104,65 @@ func NewError(e string, status ...int) error {
 	}
 }
 
+// NewStatusError creates an HttpError with status code and its standard reason phrase as message.
+// Defaults to status 500 if status is invalid.
+func NewStatusError(status int) error {
+	file, line, _ := realCaller()
+	status = realStatus(status)
+	return HttpError{
+		Line:    line,
+		File:    file,
+		Body:    nil,
+		Status:  status,
+		Message: utils.StatusMessage(status),
+	}
+}
+
+// NewValidationError creates an HttpError with status 422 and field validation errors.
+func NewValidationError(errors map[string]string) error {
+	file, line, _ := realCaller()
+	return HttpError{
+		Line:    line,
+		File:    file,
+		Body:    nil,
+		Fields:  errors,
+		Status:  fiber.StatusUnprocessableEntity,
+		Message: utils.StatusMessage(fiber.StatusUnprocessableEntity),
+	}
+}
+
+// NewWrappedError creates an HttpError that wraps the cause error with status code.
+// Error message is taken from cause. Defaults to status 500 if status is invalid.
+func NewWrappedError(cause error, status int) error {
+	file, line, _ := realCaller()
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+
+	return HttpError{
+		Line:    line,
+		File:    file,
+		Body:    nil,
+		Cause:   cause,
+		Status:  realStatus(status),
+		Message: message,
+	}
+}
+
+// NewErrorWithStack creates an HttpError like NewError and captures the call stack.
+func NewErrorWithStack(e string, status ...int) error {
+	file, line, _ := realCaller()
+	return HttpError{
+		Line:    line,
+		File:    file,
+		Body:    nil,
+		Stack:   realStack(),
+		Status:  realStatus(status...),
+		Message: e,
+	}
+}
+
 // NewFormError creates an HttpError with a message, request context, and optional status code.
 // Includes request body data if available.
 func NewFormError(e string, ctx *fiber.Ctx, status ...int) error {
@@ -50,9 +176,17 @@ func NewFormError(e string, ctx *fiber.Ctx, status ...int) error {
 	}
 }
 
-// extractRequestBody extracts request body data from the Fiber context.
-// Handles both form data and JSON body parsing.
+// extractRequestBody extracts request body data from the Fiber context without truncation.
 func extractRequestBody(ctx *fiber.Ctx) map[string]any {
+	return ExtractRequestBody(ctx, 0)
+}
+
+// ExtractRequestBody extracts request body data from the Fiber context (e.g. for audit logging).
+// Handles both form data and JSON body parsing. Form values are prefixed with "form." and
+// uploaded files with "file." described as "name [size] (mime)".
+// String values longer than maxFieldLen characters are truncated, 0 disables truncation.
+// File descriptions are never truncated.
+func ExtractRequestBody(ctx *fiber.Ctx, maxFieldLen int) map[string]any {
 	if ctx == nil {
 		return nil
 	}
@@ -99,20 +233,97 @@ func extractRequestBody(ctx *fiber.Ctx) map[string]any {
 		}
 	}
 
+	// Truncate long values
+	if maxFieldLen > 0 {
+		for k, v := range body {
+			if !strings.HasPrefix(k, "file.") {
+				body[k] = truncateValue(v, maxFieldLen)
+			}
+		}
+	}
+
 	return body
 }
 
+// truncateValue truncates strings of value to limit characters recursively.
+func truncateValue(value any, limit int) any {
+	switch v := value.(type) {
+	case string:
+		if runes := []rune(v); len(runes) > limit {
+			return string(runes[:limit]) + "..."
+		}
+		return v
+	case []string:
+		result := make([]string, len(v))
+		for i, item := range v {
+			result[i] = truncateValue(item, limit).(string)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = truncateValue(item, limit)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = truncateValue(item, limit)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// redactBody replaces sensitive fields of request body data with "[REDACTED]".
+// Fields are matched case-insensitively with or without "form." and "file." prefixes.
+// Nested objects and arrays of objects are redacted recursively.
+func redactBody(body map[string]any, fields []string) map[string]any {
+	if len(body) == 0 || len(fields) == 0 {
+		return body
+	}
+
+	isSensitive := func(k string) bool {
+		k = strings.ToLower(k)
+		k = strings.TrimPrefix(k, "form.")
+		k = strings.TrimPrefix(k, "file.")
+		return slices.Contains(fields, k)
+	}
+
+	result := make(map[string]any, len(body))
+	for k, v := range body {
+		if isSensitive(k) {
+			result[k] = "[REDACTED]"
+		} else {
+			result[k] = redactValue(v, fields)
+		}
+	}
+
+	return result
+}
+
+// redactValue redacts nested objects of value.
+func redactValue(value any, fields []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactBody(v, fields)
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = redactValue(item, fields)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
 // detectMime determines the MIME type of a file.
 // Returns "?" if the MIME type cannot be determined.
 func detectMime(file *multipart.FileHeader) string {
-	f, err := file.Open()
-	if err != nil {
-		return "?"
-	}
-	defer f.Close()
-
-	if mime, err := mimetype.DetectReader(f); err == nil && mime != nil {
-		return mime.String()
+	if mime, err := DetectMime(file); err == nil {
+		return mime
 	}
 
 	return "?"
@@ -126,10 +337,29 @@ func realCaller() (string, int, bool) {
 	return "", 0, false
 }
 
+// realStack retrieves the caller stack frames (up to 32 frames).
+func realStack() []string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]string, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
+
 // realStatus validates and returns an HTTP status code.
+// Accepts redirect (3xx) and error (4xx, 5xx) codes.
 // Defaults to 500 if the provided status is invalid.
 func realStatus(statuses ...int) int {
-	if len(statuses) > 0 && statuses[0] > 399 && statuses[0] < 600 {
+	if len(statuses) > 0 && statuses[0] > 299 && statuses[0] < 600 {
 		return statuses[0]
 	}
 
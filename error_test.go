@@ -0,0 +1,236 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/inhies/go-bytesize"
+)
+
+func decode(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestHttpErrorMarshalJSON(t *testing.T) {
+	he := HttpError{
+		Line:    10,
+		File:    "main.go",
+		Body:    map[string]any{"password": "secret"},
+		Extra:   map[string]any{"code": "E1", "tenant": "t1"},
+		Cause:   errors.New("db down"),
+		Fields:  map[string]string{"name": "required"},
+		Status:  fiber.StatusBadRequest,
+		Message: "invalid",
+	}
+
+	data, err := json.Marshal(he)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := decode(t, data)
+	for _, key := range []string{"status", "message", "code", "fields"} {
+		if _, ok := result[key]; !ok {
+			t.Errorf("public field %q missing", key)
+		}
+	}
+	for _, key := range []string{"file", "line", "body", "extra", "stack", "cause"} {
+		if _, ok := result[key]; ok {
+			t.Errorf("internal field %q exposed", key)
+		}
+	}
+
+	data, err = json.Marshal(he.Debug())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result = decode(t, data)
+	if result["file"] != "main.go" || result["cause"] != "db down" {
+		t.Errorf("debug JSON = %v, want internal fields", result)
+	}
+}
+
+func TestJSONErrorResponse(t *testing.T) {
+	he := HttpError{
+		File:    "main.go",
+		Extra:   map[string]any{"code": "E1"},
+		Status:  fiber.StatusNotFound,
+		Message: "not found",
+	}
+
+	for _, debug := range []bool{false, true} {
+		app := fiber.New()
+		app.Get("/", func(c *fiber.Ctx) error {
+			c.Locals("REQUEST_ID", "req-1")
+			return JSONErrorResponse(debug)(c, he)
+		})
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+
+		// Same shape as MarshalJSON with request id
+		expected := he
+		if debug {
+			expected = he.Debug()
+		}
+		data, _ := json.Marshal(expected)
+		want := decode(t, data)
+		want["request_id"] = "req-1"
+
+		got := decode(t, body)
+		if resp.StatusCode != fiber.StatusNotFound {
+			t.Errorf("debug %v: status = %d, want 404", debug, resp.StatusCode)
+		}
+		if len(got) != len(want) {
+			t.Errorf("debug %v: response = %v, want %v", debug, got, want)
+		}
+		for k := range want {
+			if _, ok := got[k]; !ok {
+				t.Errorf("debug %v: field %q missing", debug, k)
+			}
+		}
+	}
+}
+
+func TestNewErrorWithStack(t *testing.T) {
+	err := NewErrorWithStack("failed")
+
+	var he HttpError
+	if !errors.As(err, &he) || len(he.Stack) == 0 {
+		t.Fatalf("NewErrorWithStack() = %#v, want stack", err)
+	}
+	if !strings.Contains(he.Stack[0], "TestNewErrorWithStack") {
+		t.Errorf("stack[0] = %q, want calling function", he.Stack[0])
+	}
+}
+
+func TestHttpErrorUnwrap(t *testing.T) {
+	cause := errors.New("db down")
+	err := fmt.Errorf("handler: %w", NewWrappedError(cause, fiber.StatusServiceUnavailable))
+
+	var he HttpError
+	if !errors.As(err, &he) {
+		t.Fatal("errors.As failed to extract HttpError")
+	}
+	if he.Status != fiber.StatusServiceUnavailable || he.Message != "db down" {
+		t.Errorf("HttpError = %+v", he)
+	}
+	if he.Unwrap() != cause || !errors.Is(err, cause) {
+		t.Error("cause not unwrapped")
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected int
+		message  string
+	}{
+		{fiber.StatusNotFound, fiber.StatusNotFound, "Not Found"},
+		{fiber.StatusForbidden, fiber.StatusForbidden, "Forbidden"},
+		{fiber.StatusServiceUnavailable, fiber.StatusServiceUnavailable, "Service Unavailable"},
+		{999, fiber.StatusInternalServerError, "Internal Server Error"},
+		{0, fiber.StatusInternalServerError, "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		he := NewStatusError(tt.status).(HttpError)
+		if he.Status != tt.expected || he.Message != tt.message {
+			t.Errorf("NewStatusError(%d) = %d %q, want %d %q", tt.status, he.Status, he.Message, tt.expected, tt.message)
+		}
+	}
+}
+
+func TestNewErrorStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected int
+	}{
+		{fiber.StatusMovedPermanently, fiber.StatusMovedPermanently},
+		{fiber.StatusFound, fiber.StatusFound},
+		{fiber.StatusTemporaryRedirect, fiber.StatusTemporaryRedirect},
+		{fiber.StatusPermanentRedirect, fiber.StatusPermanentRedirect},
+		{fiber.StatusBadRequest, fiber.StatusBadRequest},
+		{fiber.StatusOK, fiber.StatusInternalServerError},
+		{fiber.StatusNoContent, fiber.StatusInternalServerError},
+		{600, fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if he := NewError("error", tt.status).(HttpError); he.Status != tt.expected {
+			t.Errorf("NewError(%d) status = %d, want %d", tt.status, he.Status, tt.expected)
+		}
+	}
+}
+
+func TestExtractRequestBody(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.JSON(ExtractRequestBody(c, 5))
+	})
+
+	// Multipart form
+	pdf := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("note", "long note value")
+	w.WriteField("short", "ok")
+	part, _ := w.CreateFormFile("document", "long-report-name.pdf")
+	part.Write(pdf)
+	w.Close()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", &buf)
+	req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	body := decode(t, raw)
+
+	if body["form.note"] != "long ..." || body["form.short"] != "ok" {
+		t.Errorf("form values = %v, %v, want truncated note", body["form.note"], body["form.short"])
+	}
+	file := fmt.Sprintf("long-report-name.pdf [%s] (application/pdf)", bytesize.New(float64(len(pdf))))
+	if files, ok := body["file.document"].([]any); !ok || len(files) != 1 || files[0] != file {
+		t.Errorf("file.document = %v, want [%s]", body["file.document"], file)
+	}
+
+	// JSON body
+	req = httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(`{"name":"long name","tags":["long tag"],"nested":{"key":"long value"}}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ = io.ReadAll(resp.Body)
+	body = decode(t, raw)
+
+	if body["form.name"] != "long ..." {
+		t.Errorf("form.name = %v, want truncated", body["form.name"])
+	}
+	if tags, ok := body["form.tags"].([]any); !ok || len(tags) != 1 || tags[0] != "long ..." {
+		t.Errorf("form.tags = %v, want truncated", body["form.tags"])
+	}
+	if nested, ok := body["form.nested"].(map[string]any); !ok || nested["key"] != "long ..." {
+		t.Errorf("form.nested = %v, want truncated", body["form.nested"])
+	}
+}
@@ -0,0 +1,75 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Headers creates a new ETag middleware with the provided options.
+// It hashes successful GET and HEAD response bodies, sets the ETag header and
+// sends 304 Not Modified if request If-None-Match header matches.
+//
+// Response body must be buffered in memory to be hashed, so avoid this middleware
+// for large or streamed responses.
+func Headers(options ...Option) fiber.Handler {
+	// Generate option
+	option := &option{
+		weak: false,
+		next: nil,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+
+	return func(c *fiber.Ctx) error {
+		// Skip
+		if option.next != nil && option.next(c) {
+			return c.Next()
+		}
+
+		// Process request
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		// Skip unsafe methods, unsuccessful or already tagged responses
+		if (c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead) ||
+			c.Response().StatusCode() != fiber.StatusOK ||
+			len(c.Response().Header.Peek(fiber.HeaderETag)) > 0 {
+			return nil
+		}
+
+		// Generate tag
+		hash := sha256.Sum256(c.Response().Body())
+		tag := `"` + hex.EncodeToString(hash[:16]) + `"`
+		if option.weak {
+			tag = "W/" + tag
+		}
+		c.Set(fiber.HeaderETag, tag)
+
+		// Check match
+		if matches(c.Get(fiber.HeaderIfNoneMatch), tag) {
+			c.Status(fiber.StatusNotModified)
+			c.Context().ResetBody()
+			return nil
+		}
+
+		return nil
+	}
+}
+
+// matches checks if If-None-Match header matches the tag using weak comparison.
+func matches(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package etag
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newApp(options ...Option) *fiber.App {
+	app := fiber.New()
+	app.Use(Headers(options...))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("hello world")
+	})
+	app.Post("/", func(c *fiber.Ctx) error {
+		return c.SendString("hello world")
+	})
+	return app
+}
+
+func TestETag(t *testing.T) {
+	app := newApp()
+
+	// Miss
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := resp.Header.Get(fiber.HeaderETag)
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || tag == "" || string(body) != "hello world" {
+		t.Fatalf("miss response = %d %q %q, want 200 with ETag", resp.StatusCode, tag, body)
+	}
+
+	// Hit
+	for _, header := range []string{tag, "W/" + tag, `"other", ` + tag, "*"} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, header)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != fiber.StatusNotModified || len(body) != 0 {
+			t.Errorf("If-None-Match %q: response = %d %q, want 304", header, resp.StatusCode, body)
+		}
+	}
+
+	// Stale
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, `"stale"`)
+	if resp, _ := app.Test(req); resp.StatusCode != fiber.StatusOK {
+		t.Errorf("stale tag status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestETagSkipsUnsafeMethods(t *testing.T) {
+	resp, err := newApp().Test(httptest.NewRequest(fiber.MethodPost, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := resp.Header.Get(fiber.HeaderETag); tag != "" {
+		t.Errorf("POST ETag = %q, want empty", tag)
+	}
+}
+
+func TestWeakETag(t *testing.T) {
+	resp, err := newApp(WithWeak()).Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := resp.Header.Get(fiber.HeaderETag); !strings.HasPrefix(tag, `W/"`) {
+		t.Errorf("ETag = %q, want weak tag", tag)
+	}
+}
@@ -0,0 +1,26 @@
+package etag
+
+import "github.com/gofiber/fiber/v2"
+
+// option holds the configuration options for ETag middleware.
+type option struct {
+	weak bool
+	next func(*fiber.Ctx) bool
+}
+
+// Option defines a function type for configuring ETag Option.
+type Option func(*option)
+
+// WithWeak generates weak validators (W/"...") instead of strong ones.
+func WithWeak() Option {
+	return func(o *option) {
+		o.weak = true
+	}
+}
+
+// WithNext sets a custom function to skip ETag generation for certain requests.
+func WithNext(handler func(*fiber.Ctx) bool) Option {
+	return func(o *option) {
+		o.next = handler
+	}
+}
@@ -3,6 +3,7 @@ module github.com/go-universal/http
 go 1.24.2
 
 require (
+	github.com/andybalholm/brotli v1.1.0
 	github.com/gabriel-vasile/mimetype v1.4.8
 	github.com/go-universal/cache v0.0.1
 	github.com/go-universal/cast v0.0.1
@@ -12,10 +13,11 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/inhies/go-bytesize v0.0.0-20220417184213-4913239db9cf
 	github.com/valyala/fasthttp v1.51.0
+	golang.org/x/image v0.25.0
+	golang.org/x/text v0.23.0
 )
 
 require (
-	github.com/andybalholm/brotli v1.1.0 // indirect
 	github.com/aymerick/douceur v0.2.0 // indirect
 	github.com/cespare/xxhash/v2 v2.2.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
@@ -31,8 +33,6 @@ require (
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
-	golang.org/x/image v0.25.0 // indirect
 	golang.org/x/net v0.33.0 // indirect
 	golang.org/x/sys v0.31.0 // indirect
-	golang.org/x/text v0.23.0 // indirect
 )
@@ -0,0 +1,212 @@
+// Package testcache provides in-memory cache and queue fakes shared by package tests.
+package testcache
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/cast"
+)
+
+// Cache is a minimal in-memory cache for tests.
+// Keys never expire, TTL reports remaining time of put ttl.
+// It implements session Scanner and Locker capabilities.
+type Cache struct {
+	cache.Cache
+	mutex   sync.Mutex
+	data    map[string]any
+	expires map[string]time.Time
+}
+
+// New creates an empty in-memory cache.
+func New() *Cache {
+	return &Cache{
+		data:    make(map[string]any),
+		expires: make(map[string]time.Time),
+	}
+}
+
+func (c *Cache) Put(key string, value any, ttl *time.Duration) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.data[key] = value
+	if ttl != nil {
+		c.expires[key] = time.Now().Add(*ttl)
+	} else {
+		delete(c.expires, key)
+	}
+	return nil
+}
+
+func (c *Cache) Update(key string, value any) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.data[key]; !ok {
+		return false, nil
+	}
+	c.data[key] = value
+	return true, nil
+}
+
+func (c *Cache) Get(key string) (any, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.data[key], nil
+}
+
+func (c *Cache) Pull(key string) (any, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	value := c.data[key]
+	delete(c.data, key)
+	delete(c.expires, key)
+	return value, nil
+}
+
+func (c *Cache) Exists(key string) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	_, ok := c.data[key]
+	return ok, nil
+}
+
+func (c *Cache) Forget(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.data, key)
+	delete(c.expires, key)
+	return nil
+}
+
+func (c *Cache) TTL(key string) (time.Duration, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.data[key]; !ok {
+		return 0, nil
+	} else if exp, ok := c.expires[key]; ok {
+		return time.Until(exp), nil
+	}
+	return -1, nil
+}
+
+func (c *Cache) Cast(key string) (cast.Caster, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if v, ok := c.data[key].([]byte); ok {
+		return cast.NewCaster(string(v)), nil
+	}
+	return cast.NewCaster(c.data[key]), nil
+}
+
+func (c *Cache) Increment(key string, value int64) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	current, ok := c.data[key]
+	if !ok {
+		return false, nil
+	}
+	c.data[key] = cast.NewCaster(current).Int64Safe(0) + value
+	return true, nil
+}
+
+func (c *Cache) Decrement(key string, value int64) (bool, error) {
+	return c.Increment(key, -value)
+}
+
+// Keys returns all keys starting with prefix.
+func (c *Cache) Keys(prefix string) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	keys := make([]string, 0)
+	for k := range c.data {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// PutIfAbsent stores value with ttl only if key not exists.
+func (c *Cache) PutIfAbsent(key string, value any, ttl time.Duration) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.data[key]; ok {
+		return false, nil
+	}
+	c.data[key] = value
+	c.expires[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+// ForgetIf removes key only if its current value equals value.
+func (c *Cache) ForgetIf(key string, value any) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if v, ok := c.data[key]; !ok || v != value {
+		return false, nil
+	}
+	delete(c.data, key)
+	delete(c.expires, key)
+	return true, nil
+}
+
+// Queue is a minimal in-memory queue for tests.
+type Queue struct {
+	cache.Queue
+	mutex sync.Mutex
+	items []string
+}
+
+// NewQueue creates a queue with items.
+func NewQueue(items ...string) *Queue {
+	return &Queue{items: items}
+}
+
+func (q *Queue) Push(value any) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.items = append(q.items, cast.NewCaster(value).StringSafe(""))
+	return nil
+}
+
+func (q *Queue) Pull() (*string, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if len(q.items) == 0 {
+		return nil, nil
+	}
+	item := q.items[0]
+	q.items = q.items[1:]
+	return &item, nil
+}
+
+func (q *Queue) Length() (int64, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return int64(len(q.items)), nil
+}
+
+// Items returns a copy of queued items.
+func (q *Queue) Items() []string {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return append([]string(nil), q.items...)
+}
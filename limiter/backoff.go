@@ -0,0 +1,51 @@
+package limiter
+
+import (
+	"time"
+
+	unicache "github.com/go-universal/cache"
+)
+
+// blocked returns the remaining progressive backoff block duration of key.
+func blocked(cache unicache.Cache, key string) (time.Duration, error) {
+	exists, err := cache.Exists(key + "-blocked")
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	return cache.TTL(key + "-blocked")
+}
+
+// backoff records a violation of key and blocks key for escalated wait duration.
+// Wait is doubled for each violation up to limit. Violations are forgotten
+// if key is not blocked again for twice the last wait duration.
+func backoff(cache unicache.Cache, key string, wait, limit time.Duration) (time.Duration, error) {
+	// Count violations
+	violations := 0
+	if exists, err := cache.Exists(key + "-violations"); err != nil {
+		return 0, err
+	} else if exists {
+		caster, err := cache.Cast(key + "-violations")
+		if err != nil {
+			return 0, err
+		}
+		violations = caster.IntSafe(0)
+	}
+
+	// Escalate wait
+	for i := 0; i < violations && wait < limit; i++ {
+		wait *= 2
+	}
+	wait = min(wait, limit)
+
+	// Store violation and block
+	ttl := 2 * wait
+	if err := cache.Put(key+"-violations", violations+1, &ttl); err != nil {
+		return 0, err
+	}
+	if err := cache.Put(key+"-blocked", true, &wait); err != nil {
+		return 0, err
+	}
+
+	return wait, nil
+}
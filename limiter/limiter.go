@@ -0,0 +1,104 @@
+package limiter
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	unicache "github.com/go-universal/cache"
+	"github.com/go-universal/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Key generates the rate limiter key used by middleware for request.
+// Options must be the same as the options passed to the middleware.
+func Key(c *fiber.Ctx, options ...Option) string {
+	return newOption(options...).buildKey(c)
+}
+
+// Reset clears the rate limiter attempts for key.
+// Use Key to generate the same key the middleware uses.
+//
+// Progressive backoff block and violations of key are cleared too.
+//
+//	if loggedIn {
+//		limiter.Reset(cache, limiter.Key(c, options...))
+//	}
+func Reset(cache unicache.Cache, key string) error {
+	if err := cache.Forget(key + "-blocked"); err != nil {
+		return err
+	}
+	if err := cache.Forget(key + "-violations"); err != nil {
+		return err
+	}
+	return unicache.NewRateLimiter(key, 1, time.Minute, cache).Reset()
+}
+
+// Hit checks the rate limit of key and records an attempt if allowed.
+// It returns whether attempt is allowed, the remaining attempts and the wait time until next
+// allowed attempt. It can be used outside of HTTP middleware (e.g. background jobs).
+func Hit(cache unicache.Cache, key string, attempts uint, ttl time.Duration) (allowed bool, remaining uint, retryAfter time.Duration, err error) {
+	return hit(unicache.NewRateLimiter(key, uint32(attempts), ttl, cache), true)
+}
+
+// hit checks the rate limiter lock and records an attempt if record is true.
+func hit(limiter unicache.RateLimiter, record bool) (bool, uint, time.Duration, error) {
+	// Check lock
+	if lock, err := limiter.MustLock(); err != nil {
+		return false, 0, 0, err
+	} else if lock {
+		until, err := limiter.AvailableIn()
+		if err != nil {
+			return false, 0, 0, err
+		}
+		return false, 0, until, nil
+	}
+
+	// Hit tries
+	if record {
+		if err := limiter.Hit(); err != nil {
+			return false, 0, 0, err
+		}
+	}
+
+	// Get left retries
+	left, err := limiter.RetriesLeft()
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	return true, uint(left), 0, nil
+}
+
+// statusOf resolves response status code of request handled with err.
+func statusOf(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var he http.HttpError
+	var fe *fiber.Error
+	if errors.As(err, &he) {
+		return he.Status
+	} else if errors.As(err, &fe) {
+		return fe.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// JSONFail creates a fail handler that sends 429 Too Many Requests status
+// with Retry-After header and {"retry_after_seconds": N} JSON body.
+//
+//	limiter.NewMiddleware(cache, limiter.WithFail(limiter.JSONFail()))
+func JSONFail() func(time.Duration) fiber.Handler {
+	return func(until time.Duration) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			seconds := int(math.Ceil(until.Seconds()))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"retry_after_seconds": seconds,
+			})
+		}
+	}
+}
@@ -2,7 +2,6 @@ package limiter
 
 import (
 	"strconv"
-	"strings"
 	"time"
 
 	unicache "github.com/go-universal/cache"
@@ -14,17 +13,7 @@ import (
 // The middleware limits the number of requests a client can make within a specified time period.
 func NewMiddleware(cache unicache.Cache, options ...Option) fiber.Handler {
 	// Generate option
-	option := &option{
-		key:      "limiter",
-		attempts: 100,
-		ttl:      time.Minute,
-		fail:     nil,
-		next:     nil,
-		keys:     nil,
-	}
-	for _, opt := range options {
-		opt(option)
-	}
+	option := newOption(options...)
 
 	return func(c *fiber.Ctx) error {
 		// Skip
@@ -33,31 +22,52 @@ func NewMiddleware(cache unicache.Cache, options ...Option) fiber.Handler {
 		}
 
 		// Create limiter
-		key := option.key + "-" + c.IP()
-		if option.keys != nil {
-			for _, k := range option.keys(c) {
-				k = strings.TrimSpace(k)
-				if k != "" {
-					key += "-" + k
-				}
-			}
-		}
+		key := option.buildKey(c)
 		limiter := unicache.NewRateLimiter(
 			key,
-			uint32(option.attempts),
+			uint32(option.attemptsFor(c)),
 			option.ttl,
 			cache,
 		)
 
-		// Lock request
-		if lock, err := limiter.MustLock(); err != nil {
-			return err
-		} else if lock {
-			until, err := limiter.AvailableIn()
+		var (
+			allowed bool
+			left    uint
+			until   time.Duration
+			err     error
+		)
+
+		// Check global cap
+		if option.total > 0 {
+			global := unicache.NewRateLimiter(option.key+"-global", uint32(option.total), option.totalTTL, cache)
+			if _, _, until, err = hit(global, true); err != nil {
+				return err
+			}
+		}
+
+		// Check backoff block, dry run never persists blocks
+		persist := option.backoff > 0 && option.dryRun == nil
+		if persist && until <= 0 {
+			if until, err = blocked(cache, key); err != nil {
+				return err
+			}
+		}
+
+		// Check tries, attempt is counted after handler
+		if until <= 0 {
+			allowed, left, until, err = hit(limiter, false)
 			if err != nil {
 				return err
+			} else if !allowed && persist {
+				if until, err = backoff(cache, key, until, option.backoff); err != nil {
+					return err
+				}
 			}
+		}
 
+		if option.dryRun != nil {
+			option.dryRun(c, !allowed)
+		} else if !allowed {
 			c.Append("Access-Control-Expose-Headers", "X-LIMIT-UNTIL")
 			c.Set("X-LIMIT-UNTIL", until.String())
 			if option.fail != nil {
@@ -68,24 +78,30 @@ func NewMiddleware(cache unicache.Cache, options ...Option) fiber.Handler {
 		}
 
 		// Move on
-		err := c.Next()
+		err = c.Next()
 
-		// Hit tries
-		if !option.skipFail || (option.skipFail && err == nil) {
-			err := limiter.Hit()
-			if err != nil {
-				return err
+		// Hit tries of request, failed request is skipped with WithSkipFail
+		if option.failures == nil && (!option.skipFail || err == nil) {
+			_, l, _, hitErr := hit(limiter, true)
+			if hitErr != nil {
+				return hitErr
 			}
+			left = l
 		}
 
-		// Send left retries to client
-		if left, err := limiter.RetriesLeft(); err != nil {
-			return err
-		} else {
-			c.Append("Access-Control-Expose-Headers", "X-LIMIT-REMAIN")
-			c.Set("X-LIMIT-REMAIN", strconv.Itoa(int(left)))
+		// Hit tries of failed request
+		if option.failures != nil && option.failures(statusOf(c, err)) {
+			_, l, _, hitErr := hit(limiter, true)
+			if hitErr != nil && err == nil {
+				return hitErr
+			}
+			left = l
 		}
 
+		// Send left retries to client
+		c.Append("Access-Control-Expose-Headers", "X-LIMIT-REMAIN")
+		c.Set("X-LIMIT-REMAIN", strconv.Itoa(int(left)))
+
 		return err
 	}
 }
@@ -0,0 +1,140 @@
+package limiter
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	unicache "github.com/go-universal/cache"
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDryRun(t *testing.T) {
+	store := testcache.New()
+	var reports []bool
+
+	app := fiber.New()
+	app.Use(NewMiddleware(
+		store,
+		WithMaxAttempts(2),
+		WithProgressiveBackoff(time.Minute),
+		WithDryRun(func(c *fiber.Ctx, over bool) {
+			reports = append(reports, over)
+		}),
+	))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	for i := range 4 {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Errorf("request %d: status = %d, want 200", i+1, resp.StatusCode)
+		}
+	}
+
+	expected := []bool{false, false, true, true}
+	if len(reports) != len(expected) {
+		t.Fatalf("reports = %v, want %v", reports, expected)
+	}
+	for i := range expected {
+		if reports[i] != expected[i] {
+			t.Errorf("request %d: over = %v, want %v", i+1, reports[i], expected[i])
+		}
+	}
+
+	// Dry run must not persist backoff blocks
+	keys, _ := store.Keys("")
+	for _, key := range keys {
+		if strings.HasSuffix(key, "-blocked") {
+			t.Errorf("dry run recorded backoff block %q", key)
+		}
+	}
+}
+
+func TestCountAfterHandler(t *testing.T) {
+	store := testcache.New()
+	app := fiber.New()
+	app.Use(NewMiddleware(store, WithMaxAttempts(2)))
+	app.Get("/", func(c *fiber.Ctx) error {
+		attempts, err := unicache.NewRateLimiter(Key(c), 2, time.Minute, store).TotalAttempts()
+		if err != nil {
+			return err
+		}
+		return c.SendString(strconv.Itoa(int(attempts)))
+	})
+
+	tests := []struct {
+		status   int
+		attempts string
+		remain   string
+	}{
+		{fiber.StatusOK, "0", "1"},
+		{fiber.StatusOK, "1", "0"},
+		{fiber.StatusTooManyRequests, "", ""},
+	}
+
+	for i, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status || (tt.attempts != "" && string(body) != tt.attempts) {
+			t.Errorf("request %d: status = %d, attempts in handler = %q, want %d, %q", i+1, resp.StatusCode, body, tt.status, tt.attempts)
+		}
+		if remain := resp.Header.Get("X-LIMIT-REMAIN"); remain != tt.remain {
+			t.Errorf("request %d: X-LIMIT-REMAIN = %q, want %q", i+1, remain, tt.remain)
+		}
+	}
+}
+
+func TestIdentityBudget(t *testing.T) {
+	app := fiber.New()
+	app.Use(NewMiddleware(
+		testcache.New(),
+		WithMaxAttempts(2),
+		WithIdentity(func(c *fiber.Ctx) (string, bool) {
+			id := c.Get("X-User")
+			return id, id != ""
+		}),
+	))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	request := func(user string) int {
+		req := httptest.NewRequest("GET", "/", nil)
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	// Exhaust anonymous budget of shared ip
+	for i := range 2 {
+		if status := request(""); status != fiber.StatusOK {
+			t.Fatalf("anonymous request %d: status = %d, want 200", i+1, status)
+		}
+	}
+	if status := request(""); status != fiber.StatusTooManyRequests {
+		t.Fatalf("anonymous request 3: status = %d, want 429", status)
+	}
+
+	// Authenticated user behind same ip has own budget
+	if status := request("alice"); status != fiber.StatusOK {
+		t.Errorf("authenticated request: status = %d, want 200", status)
+	}
+}
@@ -1,6 +1,7 @@
 package limiter
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,6 +16,70 @@ type option struct {
 	fail     func(time.Duration) fiber.Handler
 	next     func(*fiber.Ctx) bool
 	keys     func(*fiber.Ctx) []string
+	keyBy    func(*fiber.Ctx) string
+	methods  map[string]uint
+	backoff  time.Duration
+	dryRun   func(c *fiber.Ctx, over bool)
+	failures func(status int) bool
+	total    uint
+	totalTTL time.Duration
+}
+
+// newOption creates option with default values and applies options.
+func newOption(options ...Option) *option {
+	option := &option{
+		key:      "limiter",
+		attempts: 100,
+		ttl:      time.Minute,
+		fail:     nil,
+		next:     nil,
+		keys:     nil,
+		keyBy:    nil,
+		methods:  nil,
+		backoff:  0,
+		dryRun:   nil,
+		failures: nil,
+		total:    0,
+		totalTTL: 0,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+	return option
+}
+
+// buildKey generates the rate limiter cache key for request.
+// Custom primary keys are namespaced to prevent collision with IP keys.
+func (o *option) buildKey(c *fiber.Ctx) string {
+	key := o.key + "-" + c.IP()
+	if o.keyBy != nil {
+		if k := strings.TrimSpace(o.keyBy(c)); k != "" {
+			key = o.key + "-by-" + k
+		}
+	}
+
+	if _, ok := o.methods[c.Method()]; ok {
+		key += "-" + strings.ToLower(c.Method())
+	}
+
+	if o.keys != nil {
+		for _, k := range o.keys(c) {
+			k = strings.TrimSpace(k)
+			if k != "" {
+				key += "-" + k
+			}
+		}
+	}
+
+	return key
+}
+
+// attemptsFor returns the maximum attempts for request method.
+func (o *option) attemptsFor(c *fiber.Ctx) uint {
+	if attempts, ok := o.methods[c.Method()]; ok {
+		return attempts
+	}
+	return o.attempts
 }
 
 // Option defines a function type for configuring Rate Limiter Option.
@@ -39,9 +104,26 @@ func WithTTl(ttl time.Duration) Option {
 }
 
 // WithSkipFail sets the option to skip limiter if request has error.
+// It disables WithCountOnlyFailures if enabled.
 func WithSkipFail(skipFail bool) Option {
 	return func(o *option) {
 		o.skipFail = skipFail
+		if skipFail {
+			o.failures = nil
+		}
+	}
+}
+
+// WithCountOnlyFailures sets the option to count only failed requests (e.g. failed logins).
+// Attempt is counted after handler if predicate returns true for response status (status >= 400 by default).
+// Handler errors are resolved to HttpError or fiber.Error status, or 500. It disables WithSkipFail.
+func WithCountOnlyFailures(predicate func(status int) bool) Option {
+	return func(o *option) {
+		if predicate == nil {
+			predicate = func(status int) bool { return status >= 400 }
+		}
+		o.failures = predicate
+		o.skipFail = false
 	}
 }
 
@@ -65,3 +147,76 @@ func WithKeys(handler func(*fiber.Ctx) []string) Option {
 		o.keys = handler
 	}
 }
+
+// WithKeyBy sets a custom function to generate primary bucket key based on the request (e.g. user id).
+// Client IP is used if function returns empty string.
+func WithKeyBy(handler func(*fiber.Ctx) string) Option {
+	return func(o *option) {
+		o.keyBy = handler
+	}
+}
+
+// WithMethodLimits sets the maximum attempts per request method (e.g. larger budget for GET than POST).
+// Each listed method has its own counter, other methods use WithMaxAttempts budget.
+func WithMethodLimits(limits map[string]uint) Option {
+	return func(o *option) {
+		for method, attempts := range limits {
+			if method = strings.ToUpper(strings.TrimSpace(method)); method != "" && attempts > 0 {
+				if o.methods == nil {
+					o.methods = make(map[string]uint)
+				}
+				o.methods[method] = attempts
+			}
+		}
+	}
+}
+
+// WithProgressiveBackoff enables escalated block duration for repeated violations of the same key.
+// Block duration is doubled on each violation up to limit and passed to the fail handler.
+func WithProgressiveBackoff(limit time.Duration) Option {
+	return func(o *option) {
+		if limit > 0 {
+			o.backoff = limit
+		}
+	}
+}
+
+// WithIdentity sets a custom function to key the bucket by authenticated identity (e.g. user id).
+// Client IP is used if function returns false. It is an alternative form of WithKeyBy.
+func WithIdentity(handler func(*fiber.Ctx) (string, bool)) Option {
+	return func(o *option) {
+		if handler == nil {
+			o.keyBy = nil
+			return
+		}
+
+		o.keyBy = func(c *fiber.Ctx) string {
+			if id, ok := handler(c); ok {
+				return id
+			}
+			return ""
+		}
+	}
+}
+
+// WithDryRun enables observe-only mode. Attempts are counted and handler is called with
+// whether request would have been blocked, but requests are never blocked.
+// Progressive backoff blocks are not recorded in dry run, so observed keys are not blocked later.
+// Useful to tune limits before enforcing them.
+func WithDryRun(handler func(c *fiber.Ctx, over bool)) Option {
+	return func(o *option) {
+		o.dryRun = handler
+	}
+}
+
+// WithGlobalCap sets the maximum number of attempts allowed across all keys in ttl.
+// All requests are rejected when global cap reached regardless of their own key budget.
+// Global cap is checked before the key attempt is counted.
+func WithGlobalCap(attempts uint, ttl time.Duration) Option {
+	return func(o *option) {
+		if attempts > 0 && ttl > 0 {
+			o.total = attempts
+			o.totalTTL = ttl
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package limiter
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestIdentityKey(t *testing.T) {
+	app := fiber.New()
+	option := newOption(WithIdentity(func(c *fiber.Ctx) (string, bool) {
+		id := c.Get("X-User")
+		return id, id != ""
+	}))
+
+	key := func(user string) string {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		defer app.ReleaseCtx(c)
+		if user != "" {
+			c.Request().Header.Set("X-User", user)
+		}
+		return option.buildKey(c)
+	}
+
+	anonymous, first, second := key(""), key("42"), key("43")
+	if first != "limiter-by-42" {
+		t.Errorf("authenticated key = %q, want %q", first, "limiter-by-42")
+	}
+	if anonymous != "limiter-0.0.0.0" {
+		t.Errorf("anonymous key = %q, want %q", anonymous, "limiter-0.0.0.0")
+	}
+	if anonymous == first || first == second {
+		t.Errorf("keys must be distinct: %q, %q, %q", anonymous, first, second)
+	}
+}
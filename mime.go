@@ -0,0 +1,28 @@
+package http
+
+import (
+	"errors"
+	"mime/multipart"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// DetectMime determines the MIME type of an uploaded file by its content.
+func DetectMime(file *multipart.FileHeader) (string, error) {
+	if file == nil {
+		return "", errors.New("nil file")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	mime, err := mimetype.DetectReader(f)
+	if err != nil {
+		return "", err
+	}
+
+	return mime.String(), nil
+}
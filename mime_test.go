@@ -0,0 +1,46 @@
+package http
+
+import (
+	"bytes"
+	"mime/multipart"
+	"testing"
+)
+
+// newFileHeader creates multipart file header with content.
+func newFileHeader(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	w.Close()
+
+	form, err := multipart.NewReader(&buf, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestDetectMime(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	if mime, err := DetectMime(newFileHeader(t, "doc.txt", pdf)); err != nil || mime != "application/pdf" {
+		t.Errorf("DetectMime(pdf) = %q, %v, want application/pdf", mime, err)
+	}
+
+	if mime, err := DetectMime(newFileHeader(t, "empty.bin", nil)); err != nil || mime != "text/plain" {
+		t.Errorf("DetectMime(empty) = %q, %v, want text/plain", mime, err)
+	}
+
+	if _, err := DetectMime(nil); err == nil {
+		t.Error("DetectMime(nil) returned no error")
+	}
+	if mime := detectMime(nil); mime != "?" {
+		t.Errorf("detectMime(nil) = %q, want ?", mime)
+	}
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// option holds the configuration options for error handler.
+type option struct {
+	codes     []int
+	requestID string
+	generator func() string
+	redacted  []string
+	message   string
+	status    int
+	observer  func(status int, path, method string)
+	classify  Classifier
+}
+
+// Classifier maps an application error to response status and error code.
+// It must return ok false for errors it does not classify.
+type Classifier func(err error) (status int, code string, ok bool)
+
+// Option defines a function type for configuring error handler Option.
+type Option func(*option)
+
+// WithCodes sets the status codes to log. All errors are logged if no code provided.
+func WithCodes(codes ...int) Option {
+	return func(o *option) {
+		o.codes = append(o.codes, codes...)
+	}
+}
+
+// WithRequestID enables request id correlation using the given header (X-Request-Id by default).
+// Request id is read from request header or generated if absent, logged as "request_id" field,
+// stored in context locals as "REQUEST_ID" and sent back in the response header.
+func WithRequestID(header string) Option {
+	return func(o *option) {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			header = "X-Request-Id"
+		}
+		o.requestID = header
+	}
+}
+
+// WithRequestIDGenerator sets the function used to generate missing request ids.
+func WithRequestIDGenerator(generator func() string) Option {
+	return func(o *option) {
+		if generator != nil {
+			o.generator = generator
+		}
+	}
+}
+
+// WithRedactedFields sets the request body fields to replace with "[REDACTED]" in logs.
+// Fields are matched case-insensitively against form and JSON keys (e.g. "password").
+func WithRedactedFields(fields ...string) Option {
+	return func(o *option) {
+		for _, f := range fields {
+			if f = strings.TrimSpace(f); f != "" {
+				o.redacted = append(o.redacted, strings.ToLower(f))
+			}
+		}
+	}
+}
+
+// WithDefaultMessage sets the response message for regular (non HttpError and non fiber.Error) errors.
+// The original error message is still logged as "error" field.
+func WithDefaultMessage(message string) Option {
+	return func(o *option) {
+		o.message = message
+	}
+}
+
+// WithDefaultStatus sets the status code for regular (non HttpError and non fiber.Error) errors.
+// Status must be 4xx or 5xx, otherwise it is ignored.
+func WithDefaultStatus(status int) Option {
+	return func(o *option) {
+		if status >= 400 && status <= 599 {
+			o.status = status
+		}
+	}
+}
+
+// WithObserver sets a function called for every handled error regardless of logged codes.
+// Useful to record error metrics (e.g. Prometheus counters per status).
+func WithObserver(observer func(status int, path, method string)) Option {
+	return func(o *option) {
+		o.observer = observer
+	}
+}
+
+// WithClassifier sets a function to map application errors (e.g. sql.ErrNoRows) to status codes.
+// Classifier runs before default handling and falls through to it when ok is false.
+// Classified error responds with status standard message, original error is logged as "error" field
+// and non-empty code is added to error extra fields as "code".
+func WithClassifier(classifier Classifier) Option {
+	return func(o *option) {
+		o.classify = classifier
+	}
+}
+
+// classifyError classifies error using classifier if set.
+func (o *option) classifyError(err error) (int, string, bool) {
+	if o.classify == nil {
+		return 0, "", false
+	}
+	return o.classify(err)
+}
+
+// newOption creates option with default values and applies options.
+func newOption(options ...Option) *option {
+	option := &option{
+		codes:     nil,
+		requestID: "",
+		generator: uuid.NewString,
+		redacted:  nil,
+		message:   "",
+		status:    500,
+		observer:  nil,
+		classify:  nil,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+	return option
+}
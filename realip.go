@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RealIP creates a middleware that resolves the real client IP behind trusted proxies.
+// X-Forwarded-For and X-Real-IP headers are used only if the immediate peer is a trusted proxy,
+// and the context remote address is rewritten so c.IP() returns the client IP.
+// Trusted proxies can be defined as IP (e.g. "10.0.0.1") or CIDR (e.g. "10.0.0.0/8").
+func RealIP(trustedProxies ...string) fiber.Handler {
+	// Parse trusted proxies
+	trusted := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, p := range trustedProxies {
+		p = strings.TrimSpace(p)
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip == nil {
+				continue
+			} else if ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+
+		if _, network, err := net.ParseCIDR(p); err == nil {
+			trusted = append(trusted, network)
+		}
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, network := range trusted {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *fiber.Ctx) error {
+		// Skip untrusted peer
+		if !isTrusted(c.Context().RemoteIP()) {
+			return c.Next()
+		}
+
+		// Resolve client from the right most untrusted forwarded address
+		var client net.IP
+		forwarded := strings.Split(c.Get(fiber.HeaderXForwardedFor), ",")
+		for i := len(forwarded) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(forwarded[i]))
+			if ip == nil {
+				break
+			}
+
+			client = ip
+			if !isTrusted(ip) {
+				break
+			}
+		}
+
+		if client == nil {
+			client = net.ParseIP(strings.TrimSpace(c.Get("X-Real-IP")))
+		}
+
+		// Rewrite remote address
+		if client != nil {
+			port := 0
+			if addr, ok := c.Context().RemoteAddr().(*net.TCPAddr); ok {
+				port = addr.Port
+			}
+			c.Context().SetRemoteAddr(&net.TCPAddr{IP: client, Port: port})
+		}
+
+		return c.Next()
+	}
+}
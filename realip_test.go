@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRealIP(t *testing.T) {
+	app := fiber.New()
+	app.Use(RealIP("10.0.0.1", "192.168.0.0/16"))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded string
+		realIP    string
+		expected  string
+	}{
+		{"untrusted peer spoofing", "203.0.113.9", "1.2.3.4", "5.6.7.8", "203.0.113.9"},
+		{"trusted peer", "10.0.0.1", "1.2.3.4", "", "1.2.3.4"},
+		{"trusted cidr peer", "192.168.1.5", "1.2.3.4", "", "1.2.3.4"},
+		{"spoofed left most", "10.0.0.1", "6.6.6.6, 1.2.3.4", "", "1.2.3.4"},
+		{"proxy chain", "10.0.0.1", "1.2.3.4, 192.168.1.7", "", "1.2.3.4"},
+		{"invalid forwarded", "10.0.0.1", "garbage", "1.2.3.4", "1.2.3.4"},
+		{"real ip header", "10.0.0.1", "", "1.2.3.4", "1.2.3.4"},
+		{"no headers", "10.0.0.1", "", "", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		var req fasthttp.Request
+		req.SetRequestURI("/")
+		if tt.forwarded != "" {
+			req.Header.Set(fiber.HeaderXForwardedFor, tt.forwarded)
+		}
+		if tt.realIP != "" {
+			req.Header.Set("X-Real-IP", tt.realIP)
+		}
+
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Init(&req, &net.TCPAddr{IP: net.ParseIP(tt.peer), Port: 1234}, nil)
+		app.Handler()(ctx)
+
+		if ip := string(ctx.Response.Body()); ip != tt.expected {
+			t.Errorf("%s: IP = %q, want %q", tt.name, ip, tt.expected)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package http
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"slices"
 
 	"github.com/go-universal/logger"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 )
 
 // ErrorCallback is a function type that handles custom error responses.
@@ -15,9 +17,18 @@ type ErrorCallback func(ctx *fiber.Ctx, err HttpError) error
 // NewFiberErrorHandler creates a new Fiber error handler with logging and custom error response capabilities.
 // It takes a logger, an optional error callback, and a list of status codes to log.
 // If the error matches one of the provided status codes, it will be logged using the provided logger.
+// HttpError extra fields are logged with "extra." prefix to prevent collision with other fields.
 // If an error callback is provided, it will be used to handle the error response; otherwise, a default plain text response will be sent.
 // For relative file name in log use os.Setenv("APP_ROOT", "your/project/root") to define your project root.
 func NewFiberErrorHandler(l logger.Logger, cb ErrorCallback, codes ...int) fiber.ErrorHandler {
+	return NewErrorHandler(l, cb, WithCodes(codes...))
+}
+
+// NewErrorHandler creates a new Fiber error handler like NewFiberErrorHandler with extra options.
+func NewErrorHandler(l logger.Logger, cb ErrorCallback, options ...Option) fiber.ErrorHandler {
+	// Generate option
+	option := newOption(options...)
+
 	// Helper function to get the relative path of a file
 	relative := func(path string) string {
 		root := filepath.ToSlash(os.Getenv("APP_ROOT"))
@@ -37,25 +48,62 @@ func NewFiberErrorHandler(l logger.Logger, cb ErrorCallback, codes ...int) fiber
 			file    string
 			line    int
 			body    map[string]any
+			extra   map[string]any
+			stack   []string
+			cause   string
+			fields  map[string]string
 			status  = fiber.StatusInternalServerError
 			message = "Internal Server Error"
 		)
 
-		if fe, ok := err.(*fiber.Error); ok { // Parse Fiber error
-			status = fe.Code
-			message = fe.Error()
-		} else if he, ok := err.(HttpError); ok { // Parse custom HttpError
+		var fe *fiber.Error
+		var he HttpError
+		if cs, code, ok := option.classifyError(err); ok { // Parse classified error
+			status = realStatus(cs)
+			message = utils.StatusMessage(status)
+			cause = err.Error()
+			if code != "" {
+				extra = map[string]any{"code": code}
+			}
+		} else if errors.As(err, &he) { // Parse custom HttpError
 			file = he.File
 			line = he.Line
 			message = he.Error()
 			status = he.Status
-			body = he.Body
+			body = redactBody(he.Body, option.redacted)
+			extra = he.Extra
+			stack = he.Stack
+			fields = he.Fields
+		} else if errors.As(err, &fe) { // Parse Fiber error
+			status = fe.Code
+			message = fe.Error()
 		} else { // Parse regular errors
+			status = option.status
 			message = err.Error()
+			if option.message != "" {
+				cause = message
+				message = option.message
+			}
+		}
+
+		// Resolve request id
+		var requestID string
+		if option.requestID != "" {
+			requestID = ctx.Get(option.requestID)
+			if requestID == "" {
+				requestID = option.generator()
+			}
+			ctx.Set(option.requestID, requestID)
+			ctx.Locals("REQUEST_ID", requestID)
+		}
+
+		// Observe the error
+		if option.observer != nil {
+			option.observer(status, ctx.Path(), ctx.Method())
 		}
 
 		// Log the error if logger is provided and status matches the specified codes
-		if l != nil && (len(codes) == 0 || slices.Contains(codes, status)) {
+		if l != nil && (len(option.codes) == 0 || slices.Contains(option.codes, status)) {
 			params := []logger.LogOptions{
 				logger.With("file", relative(file)),
 				logger.With("line", line),
@@ -65,9 +113,24 @@ func NewFiberErrorHandler(l logger.Logger, cb ErrorCallback, codes ...int) fiber
 				logger.With("method", ctx.Method()),
 				logger.WithMessage(message),
 			}
+			if requestID != "" {
+				params = append(params, logger.With("request_id", requestID))
+			}
+			if cause != "" {
+				params = append(params, logger.With("error", cause))
+			}
+			if len(fields) > 0 {
+				params = append(params, logger.With("fields", fields))
+			}
+			if len(stack) > 0 {
+				params = append(params, logger.With("stack", stack))
+			}
 			for k, v := range body {
 				params = append(params, logger.With(k, v))
 			}
+			for k, v := range extra {
+				params = append(params, logger.With("extra."+k, v))
+			}
 			l.Error(params...)
 		}
 
@@ -77,6 +140,9 @@ func NewFiberErrorHandler(l logger.Logger, cb ErrorCallback, codes ...int) fiber
 				Line:    line,
 				File:    file,
 				Body:    body,
+				Extra:   extra,
+				Stack:   stack,
+				Fields:  fields,
 				Status:  status,
 				Message: message,
 			})
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-universal/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// captureLogger creates a file logger in temp dir and returns function to flush and read written logs.
+func captureLogger(t *testing.T) (logger.Logger, func() string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	l, err := logger.NewLogger().Path(dir).Production().Structured().Logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, func() string {
+		l.Sync()
+
+		var result strings.Builder
+		files, _ := filepath.Glob(filepath.Join(dir, "*"))
+		for _, file := range files {
+			content, _ := os.ReadFile(file)
+			result.Write(content)
+		}
+		return result.String()
+	}
+}
+
+// newErrorApp creates fiber app with error handler and handler returning err.
+func newErrorApp(handler fiber.ErrorHandler, route fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handler})
+	app.All("/", route)
+	return app
+}
+
+func TestRedactedFields(t *testing.T) {
+	l, logs := captureLogger(t)
+	app := newErrorApp(
+		NewErrorHandler(l, nil, WithRedactedFields("Password", "token")),
+		func(c *fiber.Ctx) error {
+			return NewFormError("login failed", c, fiber.StatusUnauthorized)
+		},
+	)
+
+	body := `{"username":"john","password":"s3cret-pass","nested":{"token":"s3cret-token"},"items":[{"token":"s3cret-item"}]}`
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	output := logs()
+	if strings.Contains(output, "s3cret") {
+		t.Errorf("sensitive value logged: %s", output)
+	}
+	if !strings.Contains(output, "[REDACTED]") || !strings.Contains(output, "john") {
+		t.Errorf("log = %s, want redacted password and username", output)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := map[string]any{
+		"form.password": "secret",
+		"file.Avatar":   []string{"a.png [1KB] (image/png)"},
+		"form.name":     "john",
+	}
+
+	result := redactBody(body, []string{"password", "avatar"})
+	if result["form.password"] != "[REDACTED]" || result["file.Avatar"] != "[REDACTED]" || result["form.name"] != "john" {
+		t.Errorf("redactBody() = %v", result)
+	}
+	if body["form.password"] != "secret" {
+		t.Error("original body modified")
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	l, logs := captureLogger(t)
+	app := newErrorApp(
+		NewErrorHandler(l, nil, WithRequestID(""), WithRequestIDGenerator(func() string { return "generated-id" })),
+		func(c *fiber.Ctx) error {
+			return NewError("failed", fiber.StatusBadRequest)
+		},
+	)
+
+	for _, id := range []string{"client-id", ""} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		if id != "" {
+			req.Header.Set("X-Request-Id", id)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := id
+		if expected == "" {
+			expected = "generated-id"
+		}
+		if got := resp.Header.Get("X-Request-Id"); got != expected {
+			t.Errorf("response request id = %q, want %q", got, expected)
+		}
+	}
+
+	output := logs()
+	for _, id := range []string{"client-id", "generated-id"} {
+		if !strings.Contains(output, `"request_id": "`+id+`"`) {
+			t.Errorf("request id %q not logged: %s", id, output)
+		}
+	}
+}
+
+func TestDefaultMessageAndStatus(t *testing.T) {
+	l, logs := captureLogger(t)
+	app := newErrorApp(
+		NewErrorHandler(l, nil, WithDefaultMessage("Something went wrong"), WithDefaultStatus(fiber.StatusServiceUnavailable)),
+		func(c *fiber.Ctx) error {
+			return errors.New("connection refused")
+		},
+	)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusServiceUnavailable || string(body) != "Something went wrong" {
+		t.Errorf("response = %d %q, want 503 default message", resp.StatusCode, body)
+	}
+	if output := logs(); !strings.Contains(output, "connection refused") {
+		t.Errorf("original error not logged: %s", output)
+	}
+}
+
+func TestValidationErrorCallback(t *testing.T) {
+	fields := map[string]string{"name": "required", "email": "invalid"}
+
+	var received HttpError
+	app := newErrorApp(
+		NewErrorHandler(nil, func(c *fiber.Ctx, err HttpError) error {
+			received = err
+			return c.SendStatus(err.Status)
+		}),
+		func(c *fiber.Ctx) error {
+			return NewValidationError(fields)
+		},
+	)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != fiber.StatusUnprocessableEntity || !maps.Equal(received.Fields, fields) {
+		t.Errorf("callback error = %d %v, want 422 %v", received.Status, received.Fields, fields)
+	}
+}
+
+func TestObserver(t *testing.T) {
+	type observation struct {
+		status       int
+		path, method string
+	}
+
+	var observed []observation
+	app := newErrorApp(
+		NewErrorHandler(nil, nil, WithCodes(fiber.StatusInternalServerError), WithObserver(func(status int, path, method string) {
+			observed = append(observed, observation{status, path, method})
+		})),
+		func(c *fiber.Ctx) error {
+			return NewError("conflict", fiber.StatusConflict)
+		},
+	)
+
+	if _, err := app.Test(httptest.NewRequest(fiber.MethodPut, "/", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := observation{fiber.StatusConflict, "/", fiber.MethodPut}
+	if len(observed) != 1 || observed[0] != expected {
+		t.Errorf("observed = %v, want [%v]", observed, expected)
+	}
+}
@@ -0,0 +1,22 @@
+package http
+
+import "github.com/gofiber/fiber/v2"
+
+// JSONErrorResponse creates an ErrorCallback that sends error as JSON response.
+// Response has the same shape as HttpError JSON with request_id (if enabled) field.
+// Internal details (file, line, body, extra, stack and cause) are included only if debug is true.
+func JSONErrorResponse(debug ...bool) ErrorCallback {
+	isDebug := len(debug) > 0 && debug[0]
+	return func(ctx *fiber.Ctx, err HttpError) error {
+		if isDebug {
+			err = err.Debug()
+		}
+
+		response := err.toMap()
+		if id, ok := ctx.Locals("REQUEST_ID").(string); ok && id != "" {
+			response["request_id"] = id
+		}
+
+		return ctx.Status(err.Status).JSON(response)
+	}
+}
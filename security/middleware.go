@@ -0,0 +1,53 @@
+package security
+
+import "github.com/gofiber/fiber/v2"
+
+// Headers creates a new security headers middleware with the provided options.
+// By default it sets the following headers:
+//
+//	Strict-Transport-Security: max-age=31536000; includeSubDomains (TLS requests only)
+//	Content-Security-Policy: default-src 'self'
+//	X-Content-Type-Options: nosniff
+//	X-Frame-Options: SAMEORIGIN
+//	Referrer-Policy: strict-origin-when-cross-origin
+func Headers(options ...Option) fiber.Handler {
+	// Generate option
+	option := &option{
+		hsts:           "max-age=31536000; includeSubDomains",
+		forceHSTS:      false,
+		csp:            "default-src 'self'",
+		contentType:    "nosniff",
+		frameOptions:   "SAMEORIGIN",
+		referrerPolicy: "strict-origin-when-cross-origin",
+		next:           nil,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+
+	return func(c *fiber.Ctx) error {
+		// Skip
+		if option.next != nil && option.next(c) {
+			return c.Next()
+		}
+
+		// Set headers
+		if option.hsts != "" && (option.forceHSTS || c.Protocol() == "https") {
+			c.Set(fiber.HeaderStrictTransportSecurity, option.hsts)
+		}
+		if option.csp != "" {
+			c.Set(fiber.HeaderContentSecurityPolicy, option.csp)
+		}
+		if option.contentType != "" {
+			c.Set(fiber.HeaderXContentTypeOptions, option.contentType)
+		}
+		if option.frameOptions != "" {
+			c.Set(fiber.HeaderXFrameOptions, option.frameOptions)
+		}
+		if option.referrerPolicy != "" {
+			c.Set(fiber.HeaderReferrerPolicy, option.referrerPolicy)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func request(t *testing.T, options ...Option) http.Header {
+	t.Helper()
+
+	app := fiber.New()
+	app.Use(Headers(options...))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Header
+}
+
+func TestDefaults(t *testing.T) {
+	headers := request(t, WithForceHSTS())
+	expected := map[string]string{
+		fiber.HeaderStrictTransportSecurity: "max-age=31536000; includeSubDomains",
+		fiber.HeaderContentSecurityPolicy:   "default-src 'self'",
+		fiber.HeaderXContentTypeOptions:     "nosniff",
+		fiber.HeaderXFrameOptions:           "SAMEORIGIN",
+		fiber.HeaderReferrerPolicy:          "strict-origin-when-cross-origin",
+	}
+
+	for k, v := range expected {
+		if got := headers.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestHSTSOnlyOnTLS(t *testing.T) {
+	if got := request(t).Get(fiber.HeaderStrictTransportSecurity); got != "" {
+		t.Errorf("HSTS sent on plain request: %q", got)
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	headers := request(t,
+		WithForceHSTS(),
+		WithHSTS(24*time.Hour, false, false),
+		WithCSP("default-src 'none'"),
+		WithFrameOptions("DENY"),
+		WithReferrerPolicy(""),
+		WithContentTypeOptions(""),
+	)
+
+	expected := map[string]string{
+		fiber.HeaderStrictTransportSecurity: "max-age=86400",
+		fiber.HeaderContentSecurityPolicy:   "default-src 'none'",
+		fiber.HeaderXFrameOptions:           "DENY",
+		fiber.HeaderReferrerPolicy:          "",
+		fiber.HeaderXContentTypeOptions:     "",
+	}
+
+	for k, v := range expected {
+		if got := headers.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
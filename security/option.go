@@ -0,0 +1,83 @@
+package security
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// option holds the configuration options for security headers middleware.
+type option struct {
+	hsts           string
+	forceHSTS      bool
+	csp            string
+	contentType    string
+	frameOptions   string
+	referrerPolicy string
+	next           func(*fiber.Ctx) bool
+}
+
+// Option defines a function type for configuring security headers Option.
+type Option func(*option)
+
+// WithHSTS sets Strict-Transport-Security header. Zero maxAge disables header.
+func WithHSTS(maxAge time.Duration, includeSubDomains, preload bool) Option {
+	return func(o *option) {
+		if maxAge <= 0 {
+			o.hsts = ""
+			return
+		}
+
+		o.hsts = "max-age=" + strconv.FormatInt(int64(maxAge.Seconds()), 10)
+		if includeSubDomains {
+			o.hsts += "; includeSubDomains"
+		}
+		if preload {
+			o.hsts += "; preload"
+		}
+	}
+}
+
+// WithForceHSTS sends Strict-Transport-Security header on non-TLS requests too.
+func WithForceHSTS() Option {
+	return func(o *option) {
+		o.forceHSTS = true
+	}
+}
+
+// WithCSP sets Content-Security-Policy header. Empty policy disables header.
+func WithCSP(policy string) Option {
+	return func(o *option) {
+		o.csp = strings.TrimSpace(policy)
+	}
+}
+
+// WithContentTypeOptions sets X-Content-Type-Options header. Empty value disables header.
+func WithContentTypeOptions(value string) Option {
+	return func(o *option) {
+		o.contentType = strings.TrimSpace(value)
+	}
+}
+
+// WithFrameOptions sets X-Frame-Options header. Empty value disables header.
+func WithFrameOptions(value string) Option {
+	return func(o *option) {
+		o.frameOptions = strings.TrimSpace(value)
+	}
+}
+
+// WithReferrerPolicy sets Referrer-Policy header. Empty policy disables header.
+func WithReferrerPolicy(policy string) Option {
+	return func(o *option) {
+		o.referrerPolicy = strings.TrimSpace(policy)
+	}
+}
+
+// WithNext sets a custom function to skip security headers for certain requests.
+func WithNext(handler func(*fiber.Ctx) bool) Option {
+	return func(o *option) {
+		o.next = handler
+	}
+}
@@ -0,0 +1,44 @@
+package session
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// compressMarker prefixes compressed session data.
+// Valid JSON can never start with a null byte, so marker not collides with uncompressed data.
+const compressMarker byte = 0x00
+
+// compress gzips data and prefixes it with compress marker.
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(compressMarker)
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress ungzips data if prefixed with compress marker.
+// Data without marker returned as is.
+func decompress(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != compressMarker {
+		return data, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data[1:]))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCompress(t *testing.T) {
+	data := []byte(`{"key":"` + strings.Repeat("value", 100) + `"}`)
+
+	compressed, err := compress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compressed[0] != compressMarker || len(compressed) >= len(data) {
+		t.Errorf("compressed = %d bytes with marker %x", len(compressed), compressed[0])
+	}
+	if json.Valid(compressed) {
+		t.Error("compressed data is valid JSON")
+	}
+
+	for _, input := range [][]byte{compressed, data} {
+		raw, err := decompress(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(raw, data) {
+			t.Errorf("decompress() = %q, want %q", raw, data)
+		}
+	}
+}
+
+func TestCompressedSession(t *testing.T) {
+	app := fiber.New()
+	store := testcache.New()
+
+	for _, value := range []string{"small", strings.Repeat("large", 100)} {
+		s, err := New(newCtx(app, nil), store, WithHeader("X-Session"), WithCompression(256))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Set("value", value)
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		stored, _ := store.Get("ses-" + s.Id())
+		raw := stored.([]byte)
+		if compressed := raw[0] == compressMarker; compressed != (len(value) > 256) {
+			t.Errorf("%d bytes value: compressed = %v", len(value), compressed)
+		}
+
+		loaded, err := New(newCtx(app, map[string]string{"X-Session": s.Id()}), store, WithHeader("X-Session"), WithCompression(256))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := loaded.Cast("value").StringSafe(""); got != value {
+			t.Errorf("loaded value = %q, want %q", got, value)
+		}
+	}
+}
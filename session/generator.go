@@ -1,11 +1,30 @@
 package session
 
-import "github.com/google/uuid"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
 
 // IdGenerator is a function type that generates a new session ID as a string.
 type IdGenerator func() string
 
+// Clock is a function type that returns the current time.
+type Clock func() time.Time
+
+// Binding is a function type that generates client fingerprint from request.
+type Binding func(*fiber.Ctx) string
+
 // UUIDGenerator generates a new UUID string using the google/uuid package.
 func UUIDGenerator() string {
 	return uuid.NewString()
 }
+
+// UserAgentBinding generates fingerprint from hash of request User-Agent header.
+func UserAgentBinding(c *fiber.Ctx) string {
+	hash := sha256.Sum256([]byte(c.Get(fiber.HeaderUserAgent)))
+	return hex.EncodeToString(hash[:])
+}
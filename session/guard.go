@@ -0,0 +1,30 @@
+package session
+
+import (
+	"github.com/go-universal/http"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Require extracts the Session object from the fiber.Ctx context and checks that key exists (e.g. "user_id").
+// It returns an HttpError with 401 Unauthorized status if session not found or key not exists.
+func Require(c *fiber.Ctx, key string) (Session, error) {
+	s := Parse(c)
+	if s == nil || !s.Exists(key) {
+		return nil, http.NewStatusError(fiber.StatusUnauthorized)
+	}
+
+	return s, nil
+}
+
+// AuthGuard creates a middleware that rejects requests without authenticated session.
+// Session is authenticated if key exists in session (e.g. "user_id").
+// This middleware must be called after the session middleware.
+func AuthGuard(key string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, err := Require(c, key); err != nil {
+			return err
+		}
+
+		return c.Next()
+	}
+}
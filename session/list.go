@@ -0,0 +1,132 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-universal/cache"
+)
+
+// ErrNotScannable is returned when cache does not support listing keys.
+var ErrNotScannable = errors.New("cache does not support listing keys")
+
+// Scanner is the capability interface a cache must implement to list sessions.
+type Scanner interface {
+	// Keys returns all keys starting with prefix.
+	Keys(prefix string) ([]string, error)
+}
+
+// Info holds stored session information.
+type Info struct {
+	Id        string        // Session identifier.
+	CreatedAt *time.Time    // Session creation date (nil if not available).
+	TTL       time.Duration // Session remaining time-to-live.
+}
+
+// List returns stored sessions with the given cache key prefix ("ses-" by default).
+// Cache must implement Scanner interface, otherwise ErrNotScannable is returned.
+func List(c cache.Cache, prefix string) ([]Info, error) {
+	keys, prefix, err := scan(c, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Info, 0, len(keys))
+	for _, key := range keys {
+		ttl, err := c.TTL(key)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, Info{
+			Id:        strings.TrimPrefix(key, prefix),
+			CreatedAt: storedCreatedAt(c, key),
+			TTL:       ttl,
+		})
+	}
+
+	return result, nil
+}
+
+// PruneExpired removes stored sessions with expired time-to-live and returns the number of removed sessions.
+// Session is considered expired if cache TTL returns zero or negative duration, so cache must report
+// non-positive ttl only for expired or missing keys (not for keys without expiration).
+// Cache must implement Scanner interface, otherwise ErrNotScannable is returned.
+func PruneExpired(c cache.Cache, prefix string) (int, error) {
+	keys, _, err := scan(c, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, key := range keys {
+		ttl, err := c.TTL(key)
+		if err != nil {
+			return pruned, err
+		} else if ttl > 0 {
+			continue
+		}
+
+		if err := c.Forget(key); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// scan lists session keys with prefix, excluding session lock keys.
+// Returns the normalized prefix used for listing.
+func scan(c cache.Cache, prefix string) ([]string, string, error) {
+	scanner, ok := c.(Scanner)
+	if !ok {
+		return nil, "", ErrNotScannable
+	}
+
+	if prefix = strings.TrimSpace(prefix); prefix == "" {
+		prefix = "ses-"
+	}
+
+	keys, err := scanner.Keys(prefix)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) && !strings.HasSuffix(key, "-lock") {
+			result = append(result, key)
+		}
+	}
+
+	return result, prefix, nil
+}
+
+// storedCreatedAt reads created_at of stored session data.
+func storedCreatedAt(c cache.Cache, key string) *time.Time {
+	caster, err := c.Cast(key)
+	if err != nil {
+		return nil
+	}
+
+	encoded, err := caster.String()
+	if err != nil {
+		return nil
+	}
+
+	raw, err := decompress([]byte(encoded))
+	if err != nil {
+		return nil
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil
+	}
+
+	s := &session{data: data}
+	return s.timeLocked("created_at")
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestList(t *testing.T) {
+	app := fiber.New()
+	store := testcache.New()
+
+	ids := make([]string, 0)
+	for range 2 {
+		s, err := New(newCtx(app, nil), store, WithHeader("X-Session"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, s.Id())
+	}
+	store.PutIfAbsent("ses-"+ids[0]+"-lock", "owner", time.Minute)
+
+	for _, prefix := range []string{"", "ses-", " ses- "} {
+		infos, err := List(store, prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([]string, 0, len(infos))
+		for _, info := range infos {
+			got = append(got, info.Id)
+			if info.CreatedAt == nil || info.TTL <= 0 {
+				t.Errorf("prefix %q: info = %+v, want created at and ttl", prefix, info)
+			}
+		}
+		slices.Sort(got)
+		slices.Sort(ids)
+		if !slices.Equal(got, ids) {
+			t.Errorf("prefix %q: ids = %v, want %v", prefix, got, ids)
+		}
+	}
+}
+
+func TestPruneExpired(t *testing.T) {
+	store := testcache.New()
+	live, expired := time.Hour, -time.Second
+	store.Put("ses-live", []byte("{}"), &live)
+	store.Put("ses-expired", []byte("{}"), &expired)
+
+	pruned, err := PruneExpired(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned = %d, want 1", pruned)
+	}
+	if ok, _ := store.Exists("ses-live"); !ok {
+		t.Error("live session pruned")
+	}
+	if ok, _ := store.Exists("ses-expired"); ok {
+		t.Error("expired session not pruned")
+	}
+}
+
+func TestListNotScannable(t *testing.T) {
+	store := struct{ cache.Cache }{testcache.New()}
+	if _, err := List(store, ""); !errors.Is(err, ErrNotScannable) {
+		t.Errorf("error = %v, want ErrNotScannable", err)
+	}
+}
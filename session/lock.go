@@ -0,0 +1,122 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	lockTTL     = 30 * time.Second      // lockTTL is the lock expiration to release abandoned locks.
+	lockTimeout = 5 * time.Second       // lockTimeout is the maximum wait to acquire lock.
+	lockRetry   = 50 * time.Millisecond // lockRetry is the wait between lock attempts.
+)
+
+// Locker is an optional cache capability for atomic session locking.
+// Both methods must be atomic on the storage side (e.g. redis SET NX and compare-and-delete script).
+// Caches without Locker are locked with Exists and Put (SETNX pattern) and lock ownership
+// is verified by reading the lock back, which is best-effort under heavy contention.
+type Locker interface {
+	// PutIfAbsent stores value with ttl only if key not exists.
+	// Returns true if value stored.
+	PutIfAbsent(key string, value any, ttl time.Duration) (bool, error)
+
+	// ForgetIf removes key only if its current value equals value.
+	// Returns true if key removed.
+	ForgetIf(key string, value any) (bool, error)
+}
+
+// acquireLock acquires a distributed lock on session key.
+// It waits until lock released or returns error after lock timeout.
+func (s *session) acquireLock() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Skip empty session
+	if s.id == "" {
+		return nil
+	}
+
+	key := s.k() + "-lock"
+	token := uuid.NewString()
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		ok, err := s.tryLock(key, token)
+		if err != nil {
+			return err
+		} else if ok {
+			s.lock = key
+			s.owner = token
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("failed to acquire session lock")
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// tryLock stores lock key with token if lock is not held.
+func (s *session) tryLock(key, token string) (bool, error) {
+	if locker, ok := s.cache.(Locker); ok {
+		return locker.PutIfAbsent(key, token, lockTTL)
+	}
+
+	// Set if not exists and verify ownership
+	if exists, err := s.cache.Exists(key); err != nil || exists {
+		return false, err
+	}
+
+	ttl := lockTTL
+	if err := s.cache.Put(key, token, &ttl); err != nil {
+		return false, err
+	}
+
+	return s.ownsLock(key, token)
+}
+
+// ownsLock checks if lock key holds token.
+func (s *session) ownsLock(key, token string) (bool, error) {
+	if exists, err := s.cache.Exists(key); err != nil || !exists {
+		return false, err
+	}
+
+	caster, err := s.cache.Cast(key)
+	if err != nil {
+		return false, err
+	}
+
+	return caster.StringSafe("") == token, nil
+}
+
+func (s *session) Unlock() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.unlockLocked()
+}
+
+func (s *session) unlockLocked() error {
+	if s.lock == "" {
+		return nil
+	}
+
+	// Lock expired and acquired by another request is kept
+	if locker, ok := s.cache.(Locker); ok {
+		if _, err := locker.ForgetIf(s.lock, s.owner); err != nil {
+			return err
+		}
+	} else if owned, err := s.ownsLock(s.lock, s.owner); err != nil {
+		return err
+	} else if owned {
+		if err := s.cache.Forget(s.lock); err != nil {
+			return err
+		}
+	}
+
+	s.lock = ""
+	s.owner = ""
+	return nil
+}
@@ -0,0 +1,129 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-universal/cache"
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLocking(t *testing.T) {
+	// Atomic Locker capability and SETNX pattern on plain cache
+	stores := map[string]cache.Cache{
+		"locker": testcache.New(),
+		"setnx":  struct{ cache.Cache }{testcache.New()},
+	}
+
+	for name, store := range stores {
+		app := fiber.New()
+		first, err := New(newCtx(app, nil), store, WithHeader("X-Session"), WithLocking())
+		if err != nil {
+			t.Fatal(err)
+		}
+		first.Set("counter", 1)
+		if err := first.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		headers := map[string]string{"X-Session": first.Id()}
+		owner, err := New(newCtx(app, headers), store, WithHeader("X-Session"), WithLocking())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acquired := make(chan Session)
+		go func() {
+			s, err := New(newCtx(app, headers), store, WithHeader("X-Session"), WithLocking())
+			if err != nil {
+				t.Error(err)
+			}
+			acquired <- s
+		}()
+
+		select {
+		case <-acquired:
+			t.Fatalf("%s: lock acquired while held by another session", name)
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		if err := owner.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case s := <-acquired:
+			if s != nil {
+				s.Unlock()
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: lock not acquired after release", name)
+		}
+	}
+}
+
+func TestUnlockOwnership(t *testing.T) {
+	stores := map[string]cache.Cache{
+		"locker": testcache.New(),
+		"setnx":  struct{ cache.Cache }{testcache.New()},
+	}
+
+	for name, store := range stores {
+		app := fiber.New()
+		s, err := New(newCtx(app, nil), store, WithHeader("X-Session"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		headers := map[string]string{"X-Session": s.Id()}
+		owner, err := New(newCtx(app, headers), store, WithHeader("X-Session"), WithLocking())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Simulate lock expiry and acquire by another request
+		key := "ses-" + s.Id() + "-lock"
+		ttl := lockTTL
+		store.Put(key, "other", &ttl)
+
+		if err := owner.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+		if exists, _ := store.Exists(key); !exists {
+			t.Errorf("%s: lock of another owner released", name)
+		}
+	}
+}
+
+func TestDestroyReleasesLock(t *testing.T) {
+	app := fiber.New()
+	store := struct{ cache.Cache }{testcache.New()}
+
+	s, err := New(newCtx(app, nil), store, WithHeader("X-Session"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	key := "ses-" + s.Id() + "-lock"
+	locked, err := New(newCtx(app, map[string]string{"X-Session": s.Id()}), store, WithHeader("X-Session"), WithLocking())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := store.Exists(key); !exists {
+		t.Fatal("lock not acquired")
+	}
+
+	if err := locked.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := store.Exists(key); exists {
+		t.Error("lock not released by Destroy")
+	}
+}
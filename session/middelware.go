@@ -1,6 +1,8 @@
 package session
 
 import (
+	"errors"
+
 	"github.com/go-universal/cache"
 	"github.com/gofiber/fiber/v2"
 )
@@ -8,6 +10,10 @@ import (
 // NewMiddleware creates a new session middleware for the Fiber framework.
 // It initializes a session using the provided cache and options, sets the necessary headers,
 // stores the session in the context, and ensures the session is saved after the request is processed.
+// Session is saved only if handler succeeds, unless WithSaveOnError option is used.
+// Save error on failed request is joined with the handler error.
+// Session lock (if enabled) is released after the session is saved.
+// Session ttl is renewed on every request if WithRolling option is used.
 func NewMiddleware(cache cache.Cache, options ...Option) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Create session
@@ -15,6 +21,7 @@ func NewMiddleware(cache cache.Cache, options ...Option) fiber.Handler {
 		if err != nil {
 			return err
 		}
+		defer s.Unlock()
 
 		// Set Allowed header
 		if s.isHeader() && !s.isNoop() {
@@ -22,6 +29,13 @@ func NewMiddleware(cache cache.Cache, options ...Option) fiber.Handler {
 			c.Append("Access-Control-Allow-Headers", s.getName())
 		}
 
+		// Renew rolling session
+		if s.isRolling() {
+			if err := s.Touch(); err != nil {
+				return err
+			}
+		}
+
 		// Store to context
 		c.Locals("SESSION", s)
 
@@ -29,6 +43,10 @@ func NewMiddleware(cache cache.Cache, options ...Option) fiber.Handler {
 		err = c.Next()
 		if err == nil {
 			err = s.Save()
+		} else if s.isSaveOnError() {
+			if saveErr := s.Save(); saveErr != nil {
+				err = errors.Join(err, saveErr)
+			}
 		}
 		return err
 	}
@@ -0,0 +1,188 @@
+package session
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/gofiber/fiber/v2"
+)
+
+// newApp creates fiber app with session middleware.
+// GET /set stores "value" query in session and GET /get returns stored value with fresh flag.
+func newApp(store *testcache.Cache, options ...Option) *fiber.App {
+	app := fiber.New()
+	app.Use(NewMiddleware(store, options...))
+	app.Get("/set", func(c *fiber.Ctx) error {
+		Parse(c).Set("value", c.Query("value"))
+		return nil
+	})
+	app.Get("/get", func(c *fiber.Ctx) error {
+		s := Parse(c)
+		return c.SendString(s.Cast("value").StringSafe("") + " " + strconv.FormatBool(s.IsFresh()))
+	})
+	return app
+}
+
+// send sends GET request with headers and returns response and body.
+func send(t *testing.T, app *fiber.App, target string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+// cookie returns the named response cookie.
+func cookie(resp *http.Response, name string) *http.Cookie {
+	for _, c := range resp.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestHeaderAndCookie(t *testing.T) {
+	app := newApp(testcache.New(), WithHeaderAndCookie("session", fiber.Cookie{}))
+
+	resp, _ := send(t, app, "/set?value=stored", nil)
+	id := resp.Header.Get("session")
+	c := cookie(resp, "session")
+	if id == "" || c == nil || c.Value != id {
+		t.Fatalf("header = %q, cookie = %v, want both with same id", id, c)
+	}
+
+	sources := map[string]map[string]string{
+		"header": {"session": id},
+		"cookie": {"Cookie": "session=" + id},
+	}
+	for source, headers := range sources {
+		if _, body := send(t, app, "/get", headers); body != "stored false" {
+			t.Errorf("%s: body = %q, want stored session", source, body)
+		}
+	}
+}
+
+func TestIsFresh(t *testing.T) {
+	app := newApp(testcache.New(), WithHeader("X-Session"))
+
+	resp, body := send(t, app, "/get", nil)
+	if body != " true" {
+		t.Errorf("new session body = %q, want fresh", body)
+	}
+
+	headers := map[string]string{"X-Session": resp.Header.Get("X-Session")}
+	if _, body := send(t, app, "/get", headers); body != " false" {
+		t.Errorf("loaded session body = %q, want not fresh", body)
+	}
+}
+
+func TestPartitioned(t *testing.T) {
+	tests := []struct {
+		secure      bool
+		partitioned bool
+	}{
+		{true, true},
+		{false, false},
+	}
+
+	for _, tt := range tests {
+		app := newApp(testcache.New(), WithCookie("session", fiber.Cookie{Secure: tt.secure}), WithPartitioned())
+		resp, _ := send(t, app, "/set?value=x", nil)
+
+		header := resp.Header.Get(fiber.HeaderSetCookie)
+		if partitioned := strings.HasSuffix(header, "; Partitioned"); partitioned != tt.partitioned {
+			t.Errorf("secure %v: Set-Cookie = %q, want partitioned %v", tt.secure, header, tt.partitioned)
+		}
+		if !strings.HasPrefix(header, "session=") {
+			t.Errorf("Set-Cookie = %q, want session cookie", header)
+		}
+	}
+}
+
+func TestDestroyExpiresClient(t *testing.T) {
+	// Cookie based session
+	app := newApp(testcache.New(), WithCookie("session", fiber.Cookie{Path: "/"}))
+	app.Get("/destroy", func(c *fiber.Ctx) error {
+		return Parse(c).Destroy()
+	})
+
+	resp, _ := send(t, app, "/set?value=x", nil)
+	id := cookie(resp, "session").Value
+
+	resp, _ = send(t, app, "/destroy", map[string]string{"Cookie": "session=" + id})
+	c := cookie(resp, "session")
+	if c == nil {
+		t.Fatal("destroy did not send session cookie")
+	}
+	if c.Value != "" || c.Path != "/" || !c.Expires.Before(time.Now()) {
+		t.Errorf("cookie = %q, path %q, expires %v, want expired", c.Value, c.Path, c.Expires)
+	}
+	if _, body := send(t, app, "/get", map[string]string{"Cookie": "session=" + id}); body != " true" {
+		t.Errorf("destroyed session body = %q, want fresh", body)
+	}
+
+	// Header based session
+	app = newApp(testcache.New(), WithHeader("X-Session"))
+	app.Get("/destroy", func(c *fiber.Ctx) error {
+		return Parse(c).Destroy()
+	})
+
+	resp, _ = send(t, app, "/set?value=x", nil)
+	resp, _ = send(t, app, "/destroy", map[string]string{"X-Session": resp.Header.Get("X-Session")})
+	if id := resp.Header.Get("X-Session"); id != "" {
+		t.Errorf("X-Session = %q, want cleared header", id)
+	}
+}
+
+func TestClearCookie(t *testing.T) {
+	app := newApp(testcache.New(), WithCookie("session", fiber.Cookie{Path: "/app", Domain: "example.com"}))
+	app.Get("/logout", func(c *fiber.Ctx) error {
+		Parse(c).ClearCookie()
+		return nil
+	})
+
+	resp, _ := send(t, app, "/logout", nil)
+	header := resp.Header.Get(fiber.HeaderSetCookie)
+	c := cookie(resp, "session")
+	if c == nil {
+		t.Fatal("logout did not send session cookie")
+	}
+	if c.Value != "" || c.Path != "/app" || c.Domain != "example.com" || !c.Expires.Before(time.Now()) {
+		t.Errorf("Set-Cookie = %q, want expired session cookie", header)
+	}
+}
+
+func TestSaveOnErrorJoinsSaveError(t *testing.T) {
+	var handled error
+	app := fiber.New(fiber.Config{ErrorHandler: func(c *fiber.Ctx, err error) error {
+		handled = err
+		return c.SendStatus(fiber.StatusBadRequest)
+	}})
+	app.Use(NewMiddleware(testcache.New(), WithHeader("X-Session"), WithSaveOnError(), WithMaxSize(16)))
+	app.Get("/", func(c *fiber.Ctx) error {
+		Parse(c).Set("value", strings.Repeat("x", 32))
+		return fiber.ErrUnauthorized
+	})
+
+	send(t, app, "/", nil)
+	if !errors.Is(handled, fiber.ErrUnauthorized) || !errors.Is(handled, ErrTooLarge) {
+		t.Errorf("error = %v, want handler and save errors", handled)
+	}
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"strings"
+
+	"github.com/go-universal/cast"
+)
+
+// Namespace represents a prefix-scoped view of session data.
+// Keys are stored in session as "prefix.key".
+type Namespace interface {
+	// Set stores a value in the namespace for the given key.
+	Set(key string, value any)
+
+	// Get retrieves a value from the namespace for the given key.
+	Get(key string) any
+
+	// Delete removes a value from the namespace for the given key.
+	Delete(key string)
+
+	// Exists checks if a key exists in the namespace.
+	Exists(key string) bool
+
+	// Cast returns a Caster for the value associated with the given key.
+	Cast(key string) cast.Caster
+
+	// Keys returns the namespace keys without prefix.
+	Keys() []string
+
+	// Clear removes all namespace data.
+	Clear()
+}
+
+type namespace struct {
+	prefix  string
+	session *session
+}
+
+// newNamespace creates namespace of session with "." separated prefix,
+// so namespace "user" never matches keys of "username" namespace.
+func newNamespace(s *session, prefix string) *namespace {
+	return &namespace{
+		prefix:  strings.TrimSuffix(strings.TrimSpace(prefix), ".") + ".",
+		session: s,
+	}
+}
+
+func (n *namespace) Set(k string, v any) {
+	if k = strings.TrimSpace(k); k != "" {
+		n.session.Set(n.prefix+k, v)
+	}
+}
+
+func (n *namespace) Get(k string) any {
+	return n.session.Get(n.prefix + k)
+}
+
+func (n *namespace) Delete(k string) {
+	n.session.Delete(n.prefix + k)
+}
+
+func (n *namespace) Exists(k string) bool {
+	return n.session.Exists(n.prefix + k)
+}
+
+func (n *namespace) Cast(k string) cast.Caster {
+	return n.session.Cast(n.prefix + k)
+}
+
+func (n *namespace) Keys() []string {
+	n.session.mutex.RLock()
+	defer n.session.mutex.RUnlock()
+
+	keys := make([]string, 0)
+	for k := range n.session.data {
+		if key, ok := strings.CutPrefix(k, n.prefix); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (n *namespace) Clear() {
+	// Ignore not-exists readonly session
+	if n.session.noop {
+		return
+	}
+
+	n.session.mutex.Lock()
+	defer n.session.mutex.Unlock()
+
+	for k := range n.session.data {
+		if strings.HasPrefix(k, n.prefix) {
+			delete(n.session.data, k)
+			n.session.modified = true
+		}
+	}
+}
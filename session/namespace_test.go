@@ -0,0 +1,43 @@
+package session
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNamespace(t *testing.T) {
+	app := fiber.New()
+	s, err := New(newCtx(app, nil), testcache.New(), WithHeader("X-Session"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, username := s.Namespace("user"), s.Namespace("username.")
+	user.Set("id", 1)
+	user.Set("name", "john")
+	username.Set("id", 2)
+	s.Set("id", 3)
+
+	// Keys do not collide
+	if user.Cast("id").IntSafe(0) != 1 || username.Cast("id").IntSafe(0) != 2 || s.Cast("id").IntSafe(0) != 3 {
+		t.Errorf("ids = %v, %v, %v, want 1, 2, 3", user.Get("id"), username.Get("id"), s.Get("id"))
+	}
+	if keys := user.Keys(); !slices.Equal(slices.Sorted(slices.Values(keys)), []string{"id", "name"}) {
+		t.Errorf("user keys = %v, want [id name]", keys)
+	}
+	if keys := username.Keys(); !slices.Equal(keys, []string{"id"}) {
+		t.Errorf("username keys = %v, want [id]", keys)
+	}
+
+	// Clear removes only scoped keys
+	s.ClearNamespace("user")
+	if len(user.Keys()) != 0 || user.Exists("id") {
+		t.Error("user namespace not cleared")
+	}
+	if username.Cast("id").IntSafe(0) != 2 || s.Cast("id").IntSafe(0) != 3 {
+		t.Error("keys outside namespace cleared")
+	}
+}
@@ -4,17 +4,47 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-universal/logger"
 	"github.com/gofiber/fiber/v2"
 )
 
+// CorruptPolicy defines how corrupt stored session data is handled.
+type CorruptPolicy int
+
+const (
+	// CorruptFail returns decode error and fails session creation.
+	CorruptFail CorruptPolicy = iota
+	// CorruptRegenerate treats corrupt session as non-existent and generates a fresh session.
+	CorruptRegenerate
+	// CorruptLog logs decode error and generates a fresh session.
+	CorruptLog
+)
+
 // option represents configuration options for a session.
 type option struct {
 	ttl       time.Duration // ttl specifies the time-to-live duration for the session.
 	name      string        // name is the name of the session.
+	prefix    string        // prefix is the cache key prefix of the session.
 	header    bool          // header indicates whether the session should be stored in the header.
 	readOnly  bool          // not generate session if not exists
+	locking   bool          // locking serializes concurrent requests of same session.
+	saveError bool          // saveError saves session in middleware even if handler returns error.
+	rolling   bool          // rolling renews session ttl on every request.
+	compress  int           // compress is the encoded data size threshold to gzip session data.
+	maxSize   int           // maxSize is the maximum encoded data size to store.
 	cookie    *fiber.Cookie // cookie represents the session cookie settings.
+	partition bool          // partition adds Partitioned (CHIPS) attribute to secure session cookie.
+	sameSite  bool          // sameSite chooses cookie SameSite mode based on request site.
 	generator IdGenerator   // generator is the function used to generate session IDs.
+	retries   int           // retries is the maximum id generation attempts on collision.
+	binding   Binding       // binding generates client fingerprint to bind session to.
+	clearSite []string      // clearSite is the Clear-Site-Data directives sent on destroy.
+	noTime    bool          // noTime disables created_at stamp.
+	clock     Clock         // clock returns current time for session stamps and cookie expiry.
+	migrate   []Migration   // migrate upgrades stored data schema in order.
+	corrupt   CorruptPolicy // corrupt defines how corrupt stored data is handled.
+	logger    logger.Logger // logger logs corrupt stored data on CorruptLog policy.
+	preserve  []string      // preserve is the data keys kept on Clear.
 }
 
 // Option is a function type that modifies an Option.
@@ -29,6 +59,16 @@ func WithTTL(ttl time.Duration) Option {
 	}
 }
 
+// WithKeyPrefix returns an Option that sets the cache key prefix of the session.
+func WithKeyPrefix(prefix string) Option {
+	return func(o *option) {
+		prefix := strings.TrimSpace(prefix)
+		if prefix != "" {
+			o.prefix = prefix
+		}
+	}
+}
+
 // WithHeader sets the header name for the Option if the provided name is not empty.
 // to indicate that a header is being used. It also clears any existing cookie settings.
 func WithHeader(name string) Option {
@@ -55,6 +95,38 @@ func WithCookie(name string, cookie fiber.Cookie) Option {
 	}
 }
 
+// WithHeaderAndCookie sets the name for the Option if the provided name is not empty
+// to indicate that both header and cookie are being used.
+// Session id is read from header first then from cookie, and sent in both.
+func WithHeaderAndCookie(name string, cookie fiber.Cookie) Option {
+	return func(o *option) {
+		name := strings.TrimSpace(name)
+		if name != "" {
+			o.name = name
+			o.cookie = &cookie
+			o.header = true
+		}
+	}
+}
+
+// WithPartitioned returns an Option that adds Partitioned (CHIPS) attribute to the session cookie.
+// fiber.Cookie has no Partitioned field, so attribute is appended to the raw Set-Cookie header.
+// Attribute is only applied when cookie Secure is set, per spec.
+func WithPartitioned() Option {
+	return func(o *option) {
+		o.partition = true
+	}
+}
+
+// WithSameSiteAuto returns an Option that chooses session cookie SameSite mode per request.
+// Cross-site requests (detected via Sec-Fetch-Site or Origin headers) get SameSite=None with Secure,
+// and same-site requests get SameSite=Lax. Configured cookie SameSite is used if detection is inconclusive.
+func WithSameSiteAuto() Option {
+	return func(o *option) {
+		o.sameSite = true
+	}
+}
+
 // WithReadonly returns an Option that sets the session to read-only mode.
 // When enabled, a session will not be generated if it does not already exist.
 func WithReadonly() Option {
@@ -63,7 +135,95 @@ func WithReadonly() Option {
 	}
 }
 
+// WithLocking returns an Option that serializes concurrent requests of the same session.
+// Session is locked from load until the middleware saves it, so parallel requests
+// don't overwrite each other changes. This adds latency to parallel requests of
+// the same session and request fails if lock not acquired in 5 seconds.
+// Abandoned locks are released automatically after 30 seconds.
+// Lock is atomic if cache implements Locker, otherwise a best-effort SETNX pattern is used.
+func WithLocking() Option {
+	return func(o *option) {
+		o.locking = true
+	}
+}
+
+// WithSaveOnError returns an Option that makes the middleware save the session even if handler
+// returns an error. By default session is saved only on success. Enabling this may persist
+// partial state of failed requests, but is required for data like failed login counters.
+func WithSaveOnError() Option {
+	return func(o *option) {
+		o.saveError = true
+	}
+}
+
+// WithRolling returns an Option that makes the middleware renew session time-to-live and cookie expiry
+// on every request, so active sessions are kept alive and idle sessions expire after ttl.
+// Data is not re-encoded on renewal.
+func WithRolling() Option {
+	return func(o *option) {
+		o.rolling = true
+	}
+}
+
+// WithCompression returns an Option that gzips session data larger than threshold bytes.
+// Data smaller than threshold stored uncompressed.
+func WithCompression(threshold int) Option {
+	return func(o *option) {
+		if threshold > 0 {
+			o.compress = threshold
+		}
+	}
+}
+
+// WithMaxSize returns an Option that limits stored session data size in bytes.
+// Save returns ErrTooLarge if encoded (and compressed) data exceeds the limit.
+func WithMaxSize(size int) Option {
+	return func(o *option) {
+		if size > 0 {
+			o.maxSize = size
+		}
+	}
+}
+
+// WithClearSiteData returns an Option that sends Clear-Site-Data header on Destroy (e.g. on logout).
+// Directives are quoted automatically and default to "cookies" and "storage" if not provided.
+func WithClearSiteData(directives ...string) Option {
+	return func(o *option) {
+		o.clearSite = nil
+		for _, d := range directives {
+			if d = strings.Trim(strings.TrimSpace(d), `"`); d != "" {
+				o.clearSite = append(o.clearSite, `"`+d+`"`)
+			}
+		}
+
+		if len(o.clearSite) == 0 {
+			o.clearSite = []string{`"cookies"`, `"storage"`}
+		}
+	}
+}
+
+// WithPreservedKeys returns an Option that keeps the given data keys on Clear.
+// Use it to keep data that must survive logout, e.g. csrf.SessionKeys() for CSRF continuity.
+func WithPreservedKeys(keys ...string) Option {
+	return func(o *option) {
+		for _, k := range keys {
+			if k = strings.TrimSpace(k); k != "" {
+				o.preserve = append(o.preserve, k)
+			}
+		}
+	}
+}
+
+// WithoutTimestamp returns an Option that disables storing created_at stamp
+// to save storage space. CreatedAt returns nil when enabled.
+func WithoutTimestamp() Option {
+	return func(o *option) {
+		o.noTime = true
+	}
+}
+
 // WithGenerator returns an Options function that sets the Generator of an Option.
+// Generated ids colliding with existing sessions are regenerated up to 5 times by default.
 func WithGenerator(generator IdGenerator) Option {
 	return func(o *option) {
 		if generator != nil {
@@ -71,3 +231,65 @@ func WithGenerator(generator IdGenerator) Option {
 		}
 	}
 }
+
+// WithGeneratorRetries returns an Option that sets the maximum id generation attempts
+// on collision with existing sessions. Fresh and Regenerate fail if all attempts collide.
+func WithGeneratorRetries(retries int) Option {
+	return func(o *option) {
+		if retries > 0 {
+			o.retries = retries
+		}
+	}
+}
+
+// WithClock returns an Options function that sets the clock used for created_at and updated_at
+// stamps and cookie expiry. Session expiration is handled by cache ttl and lock waits use real time,
+// so clock does not expire sessions. Useful to control stamps in tests. Defaults to time.Now.
+func WithClock(clock Clock) Option {
+	return func(o *option) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// Migration is a function type that upgrades stored session data to next schema version.
+type Migration func(data map[string]any) error
+
+// WithMigrations returns an Option that upgrades stored session data on load.
+// Stored "schema_version" is the number of applied migrations, and pending migrations run in order.
+// Migrated session is saved at the end of request. Migrations must never be removed or reordered,
+// append new migrations to the end of list.
+func WithMigrations(migrations ...Migration) Option {
+	return func(o *option) {
+		for _, m := range migrations {
+			if m != nil {
+				o.migrate = append(o.migrate, m)
+			}
+		}
+	}
+}
+
+// WithBinding returns an Option that binds session to client fingerprint.
+// Fingerprint is stored on fresh session and session is invalidated on load if fingerprint not matched.
+// Binding function must be deterministic and must not include volatile request data.
+func WithBinding(binding Binding) Option {
+	return func(o *option) {
+		if binding != nil {
+			o.binding = binding
+		}
+	}
+}
+
+// WithCorruptPolicy returns an Option that sets how corrupt stored session data is handled.
+// By default (CorruptFail) decode error is returned and session creation fails.
+// CorruptRegenerate and CorruptLog treat corrupt session as non-existent,
+// CorruptLog also logs the decode error using the provided logger.
+func WithCorruptPolicy(policy CorruptPolicy, l ...logger.Logger) Option {
+	return func(o *option) {
+		o.corrupt = policy
+		if len(l) > 0 && l[0] != nil {
+			o.logger = l[0]
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// isCrossSite detects whether request is cross-site using Sec-Fetch-Site and Origin headers.
+// The second return value is false if detection is inconclusive.
+func isCrossSite(c *fiber.Ctx) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(c.Get("Sec-Fetch-Site"))) {
+	case "cross-site":
+		return true, true
+	case "same-origin", "same-site", "none":
+		return false, true
+	}
+
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" || origin == "null" {
+		return false, false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false, false
+	}
+
+	return !strings.EqualFold(u.Host, c.Hostname()), true
+}
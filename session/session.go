@@ -2,15 +2,21 @@ package session
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-universal/cache"
 	"github.com/go-universal/cast"
+	"github.com/go-universal/logger"
 	"github.com/gofiber/fiber/v2"
 )
 
+// ErrTooLarge is returned when encoded session data exceeds the max size limit.
+var ErrTooLarge = errors.New("session data too large")
+
 // Session represents a user session interface with methods to manage session data.
 type Session interface {
 	// Id returns the session identifier.
@@ -25,6 +31,14 @@ type Session interface {
 	// Get retrieves a value from the session for the given key.
 	Get(key string) any
 
+	// SetStruct stores JSON encoded value in the session for the given key.
+	// Use it with GetStruct to keep struct types across requests.
+	SetStruct(key string, value any) error
+
+	// GetStruct decodes value stored by SetStruct for the given key into dest.
+	// Dest is left unchanged if key not exists.
+	GetStruct(key string, dest any) error
+
 	// Delete removes a value from the session for the given key.
 	Delete(key string)
 
@@ -34,18 +48,44 @@ type Session interface {
 	// Cast returns a Caster for the value associated with the given key.
 	Cast(key string) cast.Caster
 
+	// Namespace returns a view of session data scoped to the given key prefix.
+	Namespace(prefix string) Namespace
+
+	// ClearNamespace removes all session data of the given namespace prefix.
+	ClearNamespace(prefix string)
+
 	// CreatedAt retrieves session creation date.
 	CreatedAt() *time.Time
 
+	// UpdatedAt retrieves session last data modification date.
+	UpdatedAt() *time.Time
+
+	// RemainingTTL retrieves session remaining time-to-live from storage.
+	// Returns 0 for fresh (unsaved) session.
+	RemainingTTL() (time.Duration, error)
+
 	// AddTTL extends the session's time-to-live.
 	AddTTL(ttl time.Duration) error
 
 	// SetTTL set session's time-to-live.
 	SetTTL(ttl time.Duration) error
 
-	// Destroy terminates the session.
+	// Touch renews session time-to-live and cookie expiry to configured ttl without re-encoding data.
+	// Used by middleware on every request with WithRolling option.
+	Touch() error
+
+	// Destroy terminates the session, releases the session lock,
+	// expires the session cookie and clears the session header.
 	Destroy() error
 
+	// ClearCookie sends expired session cookie to remove it from client.
+	// Destroy calls it automatically.
+	ClearCookie()
+
+	// Clear removes all session data but keeps the session identifier.
+	// Keys set by WithPreservedKeys option are kept.
+	Clear()
+
 	// Save persists the session data to storage if changed.
 	// Must be called at the end of middleware.
 	Save() error
@@ -53,12 +93,27 @@ type Session interface {
 	// Fresh generates a new session.
 	Fresh() error
 
+	// IsFresh checks if session is newly generated in current request.
+	// It is only true before Save persists the session and resets the flag.
+	IsFresh() bool
+
+	// Regenerate generates a new session identifier and keeps session data.
+	// Useful to prevent session fixation after login.
+	Regenerate() error
+
 	// Load retrieves session data from storage.
 	// Returns false if the session does not exist.
 	Load() (bool, error)
 
+	// Unlock releases the session lock acquired by WithLocking option.
+	// Lock is released only if still owned by this session.
+	// Middleware calls it automatically, call it after Save when using New directly.
+	Unlock() error
+
 	isHeader() bool
 	isNoop() bool
+	isSaveOnError() bool
+	isRolling() bool
 	getName() string
 }
 
@@ -67,11 +122,15 @@ type session struct {
 	id   string         // Unique identifier for the session.
 	opt  option         // Configuration options for the session.
 	data map[string]any // Key-value store for session data.
+	raw  []byte         // Encoded data as stored in cache.
 
 	ttl      time.Duration // Additional time-to-live for the session.
 	fresh    bool          // Flag indicating if session is fresh.
 	modified bool          // Flag indicating if session data has been modified.
+	touched  bool          // Flag indicating if only session ttl has been modified.
 	noop     bool          // Flag indicating if session should ignored on readonly mode when session not exists.
+	lock     string        // Acquired lock key of the session.
+	owner    string        // Token identifying the acquired lock owner.
 
 	ctx   *fiber.Ctx   // Fiber context associated with the session.
 	cache cache.Cache  // Cache for storing session data.
@@ -84,10 +143,26 @@ func New(ctx *fiber.Ctx, cache cache.Cache, options ...Option) (Session, error)
 	option := &option{
 		ttl:       24 * time.Hour,
 		name:      "session",
+		prefix:    "ses-",
 		header:    false,
 		readOnly:  false,
+		locking:   false,
+		saveError: false,
+		compress:  0,
+		maxSize:   0,
+		clearSite: nil,
+		noTime:    false,
+		clock:     time.Now,
+		rolling:   false,
+		migrate:   nil,
 		cookie:    &fiber.Cookie{},
+		partition: false,
+		sameSite:  false,
 		generator: UUIDGenerator,
+		retries:   5,
+		corrupt:   CorruptFail,
+		logger:    nil,
+		preserve:  nil,
 	}
 	for _, opt := range options {
 		opt(option)
@@ -97,7 +172,8 @@ func New(ctx *fiber.Ctx, cache cache.Cache, options ...Option) (Session, error)
 	var id string
 	if option.header {
 		id = ctx.Get(option.name)
-	} else {
+	}
+	if id == "" && option.cookie != nil {
 		id = ctx.Cookies(option.name)
 	}
 
@@ -110,14 +186,24 @@ func New(ctx *fiber.Ctx, cache cache.Cache, options ...Option) (Session, error)
 		ttl:      0,
 		fresh:    false,
 		modified: false,
+		touched:  false,
 		noop:     false,
+		lock:     "",
+		owner:    "",
 
 		ctx:   ctx,
 		cache: cache,
 	}
 
+	if option.locking {
+		if err := session.acquireLock(); err != nil {
+			return nil, err
+		}
+	}
+
 	ok, err := session.Load()
 	if err != nil {
+		session.Unlock()
 		return nil, err
 	}
 
@@ -126,6 +212,7 @@ func New(ctx *fiber.Ctx, cache cache.Cache, options ...Option) (Session, error)
 		if option.readOnly {
 			session.noop = true
 		} else if err := session.Fresh(); err != nil {
+			session.Unlock()
 			return nil, err
 		}
 	}
@@ -169,6 +256,38 @@ func (s *session) Get(k string) any {
 	return s.data[k]
 }
 
+func (s *session) SetStruct(k string, v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.Set(k, string(encoded))
+	return nil
+}
+
+func (s *session) GetStruct(k string, dest any) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	v, ok := s.data[k]
+	if !ok {
+		return nil
+	}
+
+	// Decode encoded value or re-encode generic value
+	encoded, isString := v.(string)
+	if !isString {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		encoded = string(raw)
+	}
+
+	return json.Unmarshal([]byte(encoded), dest)
+}
+
 func (s *session) Delete(k string) {
 	// Ignore not-exists readonly session
 	if s.noop {
@@ -197,21 +316,38 @@ func (s *session) Cast(k string) cast.Caster {
 	return cast.NewCaster(s.data[k])
 }
 
+func (s *session) Namespace(prefix string) Namespace {
+	return newNamespace(s, prefix)
+}
+
+func (s *session) ClearNamespace(prefix string) {
+	s.Namespace(prefix).Clear()
+}
+
 func (s *session) CreatedAt() *time.Time {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
 
-	raw, ok := s.data["created_at"].(string)
-	if !ok {
-		return nil
-	}
+	return s.timeLocked("created_at")
+}
 
-	t, err := time.Parse(time.RFC3339, raw)
-	if err != nil {
-		return nil
+func (s *session) UpdatedAt() *time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.timeLocked("updated_at")
+}
+
+func (s *session) RemainingTTL() (time.Duration, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	// Skip empty, fresh and not-exists readonly session
+	if s.id == "" || s.fresh || s.noop {
+		return 0, nil
 	}
 
-	return &t
+	return s.cache.TTL(s.k())
 }
 
 func (s *session) AddTTL(t time.Duration) error {
@@ -226,7 +362,7 @@ func (s *session) AddTTL(t time.Duration) error {
 
 	// Schedule update
 	s.ttl = t
-	s.modified = true
+	s.touched = true
 	return s.syncLocked()
 }
 
@@ -242,7 +378,23 @@ func (s *session) SetTTL(t time.Duration) error {
 
 	// Schedule update
 	s.ttl = -t
-	s.modified = true
+	s.touched = true
+	return s.syncLocked()
+}
+
+func (s *session) Touch() error {
+	// Skip empty, fresh and not-exists readonly session
+	if s.id == "" || s.fresh || s.noop {
+		return nil
+	}
+
+	// Safe race condition
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Schedule update
+	s.ttl = -s.opt.ttl
+	s.touched = true
 	return s.syncLocked()
 }
 
@@ -256,24 +408,66 @@ func (s *session) Destroy() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Delete from cache
+	// Delete from cache and release lock
 	err := s.cache.Forget(s.k())
 	if err != nil {
 		return err
 	}
+	if err := s.unlockLocked(); err != nil {
+		return err
+	}
 
 	// Clear data
 	s.id = ""
 	s.data = make(map[string]any)
+	s.raw = nil
 	s.ttl = 0
 	s.fresh = false
 	s.modified = false
+	s.touched = false
+	s.expireLocked()
 	return nil
 }
 
+func (s *session) ClearCookie() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clearCookieLocked()
+}
+
+func (s *session) Clear() {
+	// Ignore not-exists readonly session
+	if s.noop {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Reset data and keep preserved keys
+	data := make(map[string]any)
+	for _, k := range s.opt.preserve {
+		if v, ok := s.data[k]; ok {
+			data[k] = v
+		}
+	}
+	s.data = data
+	if !s.opt.noTime {
+		s.data["created_at"] = s.opt.clock().Format(time.RFC3339)
+	}
+	if s.opt.binding != nil {
+		s.data["fingerprint"] = s.opt.binding(s.ctx)
+	}
+	if len(s.opt.migrate) > 0 {
+		s.data["schema_version"] = len(s.opt.migrate)
+	}
+	s.modified = true
+}
+
 func (s *session) Save() error {
 	// Skip un-initialized, unchanged, destroyed and not-exists readonly session
-	if s.id == "" || (!s.fresh && !s.modified) || s.noop {
+	if s.id == "" || (!s.fresh && !s.modified && !s.touched) || s.noop {
 		return nil
 	}
 
@@ -281,10 +475,31 @@ func (s *session) Save() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	// Encode data
-	encoded, err := json.Marshal(s.data)
-	if err != nil {
-		return err
+	// Reuse stored data on ttl only change or encode data
+	encoded := s.raw
+	if s.fresh || s.modified || encoded == nil {
+		// Stamp data modification
+		if s.modified {
+			s.data["updated_at"] = s.opt.clock().Format(time.RFC3339)
+		}
+
+		var err error
+		encoded, err = json.Marshal(s.data)
+		if err != nil {
+			return err
+		}
+
+		// Compress large data
+		if s.opt.compress > 0 && len(encoded) > s.opt.compress {
+			if encoded, err = compress(encoded); err != nil {
+				return err
+			}
+		}
+
+		// Validate data size
+		if s.opt.maxSize > 0 && len(encoded) > s.opt.maxSize {
+			return fmt.Errorf("%w: %d bytes exceeds %d bytes limit", ErrTooLarge, len(encoded), s.opt.maxSize)
+		}
 	}
 
 	// Store New
@@ -311,14 +526,21 @@ func (s *session) Save() error {
 			return err
 		}
 	} else {
-		if _, err = s.cache.Update(s.k(), encoded); err != nil {
+		// Re-create session expired between load and save
+		if ok, err := s.cache.Update(s.k(), encoded); err != nil {
 			return err
+		} else if !ok {
+			if err := s.cache.Put(s.k(), encoded, &s.opt.ttl); err != nil {
+				return err
+			}
 		}
 	}
 
+	s.raw = encoded
 	s.ttl = 0
 	s.fresh = false
 	s.modified = false
+	s.touched = false
 	return nil
 }
 
@@ -341,12 +563,60 @@ func (s *session) Fresh() error {
 	}
 
 	// Set identifier and created at
-	s.id = s.opt.generator()
+	id, err := s.generateLocked()
+	if err != nil {
+		return err
+	}
+
+	s.id = id
 	s.ttl = s.opt.ttl
 	s.data = make(map[string]any)
 	s.fresh = true
 	s.modified = true
-	s.data["created_at"] = time.Now().Format(time.RFC3339)
+	if !s.opt.noTime {
+		s.data["created_at"] = s.opt.clock().Format(time.RFC3339)
+	}
+	if s.opt.binding != nil {
+		s.data["fingerprint"] = s.opt.binding(s.ctx)
+	}
+	if len(s.opt.migrate) > 0 {
+		s.data["schema_version"] = len(s.opt.migrate)
+	}
+	return s.syncLocked()
+}
+
+func (s *session) IsFresh() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.fresh
+}
+
+func (s *session) Regenerate() error {
+	// Skip empty session and not-exists readonly session
+	if s.id == "" || s.noop {
+		return nil
+	}
+
+	// Safe race condition
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Destroy old session
+	if err := s.cache.Forget(s.k()); err != nil {
+		return err
+	}
+
+	// Set new identifier and keep data
+	id, err := s.generateLocked()
+	if err != nil {
+		return err
+	}
+
+	s.id = id
+	s.ttl = s.opt.ttl
+	s.fresh = true
+	s.modified = true
 	return s.syncLocked()
 }
 
@@ -379,8 +649,26 @@ func (s *session) Load() (bool, error) {
 	}
 
 	s.data = make(map[string]any)
-	err = json.Unmarshal([]byte(encoded), &s.data)
+	raw, err := decompress([]byte(encoded))
+	if err == nil {
+		err = json.Unmarshal(raw, &s.data)
+	}
 	if err != nil {
+		return false, s.corruptLocked(err)
+	}
+	s.raw = []byte(encoded)
+
+	// Invalidate session bound to another client
+	if s.opt.binding != nil {
+		fingerprint, _ := s.data["fingerprint"].(string)
+		if fingerprint != s.opt.binding(s.ctx) {
+			s.data = make(map[string]any)
+			return false, nil
+		}
+	}
+
+	// Migrate legacy data
+	if err := s.migrateLocked(); err != nil {
 		return false, err
 	}
 
@@ -401,6 +689,20 @@ func (s *session) isNoop() bool {
 	return s.noop
 }
 
+func (s *session) isSaveOnError() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.opt.saveError
+}
+
+func (s *session) isRolling() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.opt.rolling
+}
+
 func (s *session) getName() string {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -408,8 +710,71 @@ func (s *session) getName() string {
 	return s.opt.name
 }
 
+func (s *session) generateLocked() (string, error) {
+	// Retry on collision with existing session
+	for range s.opt.retries {
+		id := s.opt.generator()
+		exists, err := s.cache.Exists(s.opt.prefix + id)
+		if err != nil {
+			return "", err
+		} else if !exists && id != "" {
+			return id, nil
+		}
+	}
+
+	return "", errors.New("failed to generate unique session id")
+}
+
+func (s *session) migrateLocked() error {
+	version := cast.NewCaster(s.data["schema_version"]).IntSafe(0)
+	if version >= len(s.opt.migrate) {
+		return nil
+	}
+
+	for _, migration := range s.opt.migrate[max(version, 0):] {
+		if err := migration(s.data); err != nil {
+			return err
+		}
+	}
+
+	s.data["schema_version"] = len(s.opt.migrate)
+	s.modified = true
+	return nil
+}
+
+func (s *session) corruptLocked(err error) error {
+	if s.opt.corrupt == CorruptFail {
+		return err
+	}
+
+	if s.opt.corrupt == CorruptLog && s.opt.logger != nil {
+		s.opt.logger.Error(
+			logger.With("session", s.id),
+			logger.With("error", err.Error()),
+			logger.WithMessage("corrupt session data"),
+		)
+	}
+
+	s.data = make(map[string]any)
+	return nil
+}
+
+func (s *session) timeLocked(k string) *time.Time {
+	raw, ok := s.data[k].(string)
+	if !ok {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil
+	}
+
+	return &t
+}
+
 func (s *session) k() string {
-	return "ses-" + s.id
+	return s.opt.prefix + s.id
 }
 
 func (s *session) syncLocked() error {
@@ -421,6 +786,10 @@ func (s *session) syncLocked() error {
 	// Send header
 	if s.opt.header {
 		s.ctx.Set(s.opt.name, s.id)
+	}
+
+	// Skip header only session
+	if s.opt.cookie == nil {
 		return nil
 	}
 
@@ -438,13 +807,25 @@ func (s *session) syncLocked() error {
 		}
 	}
 
-	s.ctx.Cookie(&fiber.Cookie{
+	// Resolve same site mode
+	secure := s.opt.cookie.Secure
+	sameSite := s.opt.cookie.SameSite
+	if s.opt.sameSite {
+		if cross, ok := isCrossSite(s.ctx); ok && cross {
+			secure = true
+			sameSite = fiber.CookieSameSiteNoneMode
+		} else if ok {
+			sameSite = fiber.CookieSameSiteLaxMode
+		}
+	}
+
+	s.cookieLocked(&fiber.Cookie{
 		Name:        s.opt.name,
 		Value:       s.id,
-		Expires:     time.Now().Add(ttl),
-		Secure:      s.opt.cookie.Secure,
+		Expires:     s.opt.clock().Add(ttl),
+		Secure:      secure,
 		Domain:      s.opt.cookie.Domain,
-		SameSite:    s.opt.cookie.SameSite,
+		SameSite:    sameSite,
 		Path:        s.opt.cookie.Path,
 		MaxAge:      s.opt.cookie.MaxAge,
 		HTTPOnly:    s.opt.cookie.HTTPOnly,
@@ -453,3 +834,46 @@ func (s *session) syncLocked() error {
 
 	return nil
 }
+
+func (s *session) expireLocked() {
+	// Clear site data
+	if len(s.opt.clearSite) > 0 {
+		s.ctx.Set("Clear-Site-Data", strings.Join(s.opt.clearSite, ", "))
+	}
+
+	// Clear header
+	if s.opt.header {
+		s.ctx.Response().Header.Del(s.opt.name)
+	}
+
+	// Expire cookie
+	s.clearCookieLocked()
+}
+
+func (s *session) clearCookieLocked() {
+	if s.opt.cookie != nil {
+		s.cookieLocked(&fiber.Cookie{
+			Name:     s.opt.name,
+			Value:    "",
+			Expires:  time.Unix(0, 0),
+			Secure:   s.opt.cookie.Secure,
+			Domain:   s.opt.cookie.Domain,
+			SameSite: s.opt.cookie.SameSite,
+			Path:     s.opt.cookie.Path,
+			MaxAge:   -1,
+			HTTPOnly: s.opt.cookie.HTTPOnly,
+		})
+	}
+}
+
+func (s *session) cookieLocked(cookie *fiber.Cookie) {
+	s.ctx.Cookie(cookie)
+
+	// Add partitioned attribute
+	if s.opt.partition && cookie.Secure {
+		header := &s.ctx.Response().Header
+		raw := string(header.PeekCookie(cookie.Name))
+		header.DelCookie(cookie.Name)
+		header.Add(fiber.HeaderSetCookie, raw+"; Partitioned")
+	}
+}
@@ -0,0 +1,296 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-universal/http/internal/testcache"
+	"github.com/go-universal/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// newCtx creates a fiber context with optional request headers.
+func newCtx(app *fiber.App, headers map[string]string) *fiber.Ctx {
+	ctx := &fasthttp.RequestCtx{}
+	for k, v := range headers {
+		ctx.Request.Header.Set(k, v)
+	}
+	return app.AcquireCtx(ctx)
+}
+
+func TestClearPreservedKeys(t *testing.T) {
+	app := fiber.New()
+	for _, preserve := range []bool{false, true} {
+		options := []Option{WithHeader("X-Session")}
+		if preserve {
+			options = append(options, WithPreservedKeys("csrf", "csrf_at"))
+		}
+
+		s, err := New(newCtx(app, nil), testcache.New(), options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id := s.Id()
+		s.Set("user", 1)
+		s.Set("csrf", "token")
+		s.Set("csrf_at", "2024-01-01T00:00:00Z")
+		s.Clear()
+
+		if s.Exists("user") {
+			t.Error("user data not cleared")
+		}
+		if kept := s.Cast("csrf").StringSafe("") == "token" && s.Exists("csrf_at"); kept != preserve {
+			t.Errorf("preserve %v: csrf kept = %v", preserve, kept)
+		}
+		if s.Id() != id {
+			t.Error("session identifier changed")
+		}
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	app := fiber.New()
+	s, err := New(newCtx(app, nil), testcache.New(), WithHeader("X-Session"), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if at := s.CreatedAt(); at == nil || !at.Equal(now) {
+		t.Errorf("CreatedAt() = %v, want %v", at, now)
+	}
+}
+
+func TestSaveRecreatesExpiredSession(t *testing.T) {
+	app := fiber.New()
+	store := testcache.New()
+
+	s, err := New(newCtx(app, nil), store, WithHeader("X-Session"), WithTTL(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]string{"X-Session": s.Id()}
+	s, err = New(newCtx(app, headers), store, WithHeader("X-Session"), WithTTL(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("user", 1)
+
+	// Expire session between load and save
+	keys, _ := store.Keys("ses-")
+	for _, key := range keys {
+		store.Forget(key)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = New(newCtx(app, headers), store, WithHeader("X-Session"), WithTTL(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Cast("user").IntSafe(0) != 1 {
+		t.Error("session data lost")
+	}
+	if ttl, err := s.RemainingTTL(); err != nil || ttl <= 0 {
+		t.Errorf("RemainingTTL() = %v, %v, want positive ttl", ttl, err)
+	}
+}
+
+func TestWithoutTimestamp(t *testing.T) {
+	app := fiber.New()
+	store := testcache.New()
+	s, err := New(newCtx(app, nil), store, WithHeader("X-Session"), WithoutTimestamp())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.Set("user", 1)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if s.UpdatedAt() == nil {
+		t.Error("updated_at not stored")
+	}
+	keys, _ := store.Keys("")
+	for _, key := range keys {
+		if stored, _ := store.Cast(key); strings.Contains(stored.StringSafe(""), "created_at") {
+			t.Errorf("stored data = %q, want without created_at", stored.StringSafe(""))
+		}
+	}
+	if s.Exists("created_at") {
+		t.Error("created_at stored")
+	}
+	if at := s.CreatedAt(); at != nil {
+		t.Errorf("CreatedAt() = %v, want nil", at)
+	}
+
+	s.Clear()
+	if s.Exists("created_at") || s.CreatedAt() != nil {
+		t.Error("created_at stored by Clear")
+	}
+}
+
+func TestCorruptPolicy(t *testing.T) {
+	app := fiber.New()
+	dir := t.TempDir()
+	l, err := logger.NewLogger().Path(dir).Production().Structured().Logger()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		policy CorruptPolicy
+	}{
+		{"fail", CorruptFail},
+		{"regenerate", CorruptRegenerate},
+		{"log", CorruptLog},
+	}
+
+	for _, tt := range tests {
+		store := testcache.New()
+		options := []Option{WithHeader("X-Session"), WithCorruptPolicy(tt.policy, l)}
+		s, err := New(newCtx(app, nil), store, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Set("user", 1)
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Inject corrupt bytes
+		id := s.Id()
+		store.Put("ses-"+id, []byte("{\"user\":1,\x00garbage"), nil)
+
+		ok, err := s.Load()
+		if ok || (err != nil) != (tt.policy == CorruptFail) {
+			t.Errorf("%s: Load() = %v, %v", tt.name, ok, err)
+		}
+
+		loaded, err := New(newCtx(app, map[string]string{"X-Session": id}), store, options...)
+		if tt.policy == CorruptFail {
+			if err == nil {
+				t.Errorf("%s: New() returned no error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: New() = %v", tt.name, err)
+		}
+		if !loaded.IsFresh() || loaded.Id() == id || loaded.Exists("user") {
+			t.Errorf("%s: corrupt session not regenerated", tt.name)
+		}
+	}
+
+	// Only log policy writes log
+	l.Sync()
+	var logs strings.Builder
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	for _, file := range files {
+		content, _ := os.ReadFile(file)
+		logs.Write(content)
+	}
+	if count := strings.Count(logs.String(), "corrupt session data"); count != 2 {
+		t.Errorf("logged %d corrupt errors, want 2 (Load and New of log policy)", count)
+	}
+}
+
+func TestGeneratorRetries(t *testing.T) {
+	app := fiber.New()
+	store := testcache.New()
+	store.Put("ses-taken", "{}", nil)
+
+	// Zero retries uses default 5 attempts
+	tests := []struct {
+		retries int
+		calls   int
+	}{
+		{0, 5},
+		{3, 3},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+		generator := func() string {
+			calls++
+			return "taken"
+		}
+
+		_, err := New(newCtx(app, nil), store, WithHeader("X-Session"), WithGenerator(generator), WithGeneratorRetries(tt.retries))
+		if err == nil {
+			t.Errorf("retries %d: colliding id accepted", tt.retries)
+		}
+		if calls != tt.calls {
+			t.Errorf("retries %d: generator calls = %d, want %d", tt.retries, calls, tt.calls)
+		}
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	app := fiber.New()
+	store := testcache.New()
+
+	// Store legacy session without schema version
+	s, err := New(newCtx(app, nil), store, WithHeader("X-Session"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("name", "john")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := make([]int, 2)
+	options := []Option{
+		WithHeader("X-Session"),
+		WithMigrations(
+			func(data map[string]any) error {
+				runs[0]++
+				data["first_name"] = data["name"]
+				delete(data, "name")
+				return nil
+			},
+			func(data map[string]any) error {
+				runs[1]++
+				data["active"] = true
+				return nil
+			},
+		),
+	}
+
+	// Outdated session is migrated on load and saved
+	headers := map[string]string{"X-Session": s.Id()}
+	s, err = New(newCtx(app, headers), store, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saved session is up to date, migrations must not run again
+	s, err = New(newCtx(app, headers), store, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs[0] != 1 || runs[1] != 1 {
+		t.Errorf("migration runs = %v, want [1 1]", runs)
+	}
+	if s.Exists("name") || s.Cast("first_name").StringSafe("") != "john" || !s.Cast("active").BoolSafe(false) {
+		t.Errorf("data not migrated: name %v, first_name %v, active %v", s.Get("name"), s.Get("first_name"), s.Get("active"))
+	}
+	if version := s.Cast("schema_version").IntSafe(0); version != 2 {
+		t.Errorf("schema_version = %d, want 2", version)
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Timeout creates a middleware that runs next handlers with a context deadline.
+// Deadline is set on c.UserContext() and cancelled after handlers return.
+//
+// Timeout is cooperative: handlers are never interrupted, so handlers must pass c.UserContext()
+// to long-running operations to stop on time. Handler ignoring the context runs to completion.
+// If deadline exceeds and handler has not written a response, an HttpError with status 504
+// is returned for error handler. Response written by handler is kept even after the deadline.
+func Timeout(d time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Skip invalid duration
+		if d <= 0 {
+			return c.Next()
+		}
+
+		// Set deadline
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		// Process request
+		err := c.Next()
+		expired := errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+		if expired && !isWritten(c) {
+			he := NewWrappedError(context.DeadlineExceeded, fiber.StatusGatewayTimeout).(HttpError)
+			he.Message = utils.StatusMessage(fiber.StatusGatewayTimeout)
+			return he
+		}
+
+		return err
+	}
+}
+
+// isWritten checks if handler has written response status or body.
+func isWritten(c *fiber.Ctx) bool {
+	resp := c.Response()
+	return resp.StatusCode() != fiber.StatusOK || len(resp.Body()) > 0 || resp.IsBodyStream()
+}
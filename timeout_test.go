@@ -0,0 +1,59 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestTimeout(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: NewErrorHandler(nil, nil)})
+	app.Get("/slow", Timeout(50*time.Millisecond), func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		case <-time.After(5 * time.Second):
+			return c.SendString("done")
+		}
+	})
+	app.Get("/fast", Timeout(time.Second), func(c *fiber.Ctx) error {
+		return c.SendString("done")
+	})
+	app.Get("/late", Timeout(50*time.Millisecond), func(c *fiber.Ctx) error {
+		time.Sleep(100 * time.Millisecond)
+		return c.SendString("done")
+	})
+
+	start := time.Now()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/slow", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusGatewayTimeout {
+		t.Errorf("slow status = %d, want 504", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("slow request took %s, want timely return", elapsed)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fast", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("fast status = %d, want 200", resp.StatusCode)
+	}
+
+	// Response written after deadline by handler ignoring context is kept
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/late", nil), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || string(body) != "done" {
+		t.Errorf("late response = %d %q, want 200 done", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,128 @@
+package uploader
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// blurHashChars is the base83 alphabet of BlurHash.
+const blurHashChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// createBlurHash generates BlurHash placeholder of src image with 4x3 components.
+// Image is downscaled to 32px box before encoding.
+// Returns empty string if src is not a supported, valid or small enough image.
+func createBlurHash(src string) (string, error) {
+	// Decode source image
+	img, _, err := decodeImage(src)
+	if err != nil || img == nil {
+		return "", err
+	}
+
+	// Downscale
+	bounds := img.Bounds()
+	w, h := thumbnailSize(bounds.Dx(), bounds.Dy(), 32, 32)
+	small := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, bounds, draw.Src, nil)
+
+	return encodeBlurHash(small, 4, 3), nil
+}
+
+// encodeBlurHash encodes image to BlurHash string with cx * cy components.
+func encodeBlurHash(img *image.RGBA, cx, cy int) string {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+	// Calculate components
+	factors := make([][3]float64, 0, cx*cy)
+	for j := range cy {
+		for i := range cx {
+			var factor [3]float64
+			for y := range h {
+				for x := range w {
+					basis := math.Cos(math.Pi*float64(i*x)/float64(w)) * math.Cos(math.Pi*float64(j*y)/float64(h))
+					offset := img.PixOffset(x, y)
+					for c := range 3 {
+						factor[c] += basis * sRGBToLinear(img.Pix[offset+c])
+					}
+				}
+			}
+
+			scale := 2.0 / float64(w*h)
+			if i == 0 && j == 0 {
+				scale = 1.0 / float64(w*h)
+			}
+			for c := range 3 {
+				factor[c] *= scale
+			}
+			factors = append(factors, factor)
+		}
+	}
+
+	// Encode size flag
+	var hash strings.Builder
+	hash.WriteString(encode83((cx-1)+(cy-1)*9, 1))
+
+	// Encode maximum AC value
+	maximum := 1.0
+	if len(factors) > 1 {
+		actual := 0.0
+		for _, f := range factors[1:] {
+			actual = max(actual, math.Abs(f[0]), math.Abs(f[1]), math.Abs(f[2]))
+		}
+
+		quantised := int(max(0, min(82, math.Floor(actual*166-0.5))))
+		maximum = float64(quantised+1) / 166
+		hash.WriteString(encode83(quantised, 1))
+	} else {
+		hash.WriteString(encode83(0, 1))
+	}
+
+	// Encode DC
+	dc := factors[0]
+	hash.WriteString(encode83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4))
+
+	// Encode AC
+	for _, f := range factors[1:] {
+		quant := func(v float64) int {
+			return int(max(0, min(18, math.Floor(signPow(v/maximum, 0.5)*9+9.5))))
+		}
+		hash.WriteString(encode83(quant(f[0])*19*19+quant(f[1])*19+quant(f[2]), 2))
+	}
+
+	return hash.String()
+}
+
+// encode83 encodes value to base83 string of length.
+func encode83(value, length int) string {
+	result := make([]byte, length)
+	for i := range length {
+		digit := value / int(math.Pow(83, float64(length-i-1))) % 83
+		result[i] = blurHashChars[digit]
+	}
+	return string(result)
+}
+
+// sRGBToLinear converts sRGB channel value to linear value.
+func sRGBToLinear(v uint8) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts linear value to sRGB channel value.
+func linearToSRGB(v float64) int {
+	v = max(0, min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow raises absolute value to exp and keeps the sign.
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
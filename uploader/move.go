@@ -0,0 +1,46 @@
+package uploader
+
+import (
+	"io"
+	"os"
+)
+
+// rename renames file, replaced in tests to simulate cross device moves.
+var rename = os.Rename
+
+// moveFile moves src file to dest, copying across devices if rename fails.
+// Source file is kept if keep is true.
+func moveFile(src, dest string, keep bool) error {
+	if !keep && rename(src, dest) == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	if keep {
+		return nil
+	}
+
+	in.Close()
+	return os.Remove(src)
+}
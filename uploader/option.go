@@ -1,6 +1,7 @@
 package uploader
 
 import (
+	"io"
 	"strings"
 
 	"github.com/go-universal/cache"
@@ -8,14 +9,45 @@ import (
 
 // option holds configuration settings for the uploader.
 type option struct {
-	queue    cache.Queue
-	numbered bool
-	prefix   string
+	queue     cache.Queue
+	numbered  bool
+	prefix    string
+	mimeLimit int64
+	ascii     bool
+	scanner   func(io.Reader) error
+	progress  func(written, total int64)
+	dedup     bool
+	strategy  NameStrategy
+	blurHash  bool
+
+	thumbWidth  int
+	thumbHeight int
+	thumbSuffix string
 }
 
 // Option defines a function type for modifying uploader option.
 type Option func(*option)
 
+// newOption creates option with default values and applies options.
+func newOption(options ...Option) *option {
+	option := &option{
+		queue:     nil,
+		numbered:  false,
+		prefix:    "",
+		mimeLimit: 0,
+		ascii:     false,
+		scanner:   nil,
+		progress:  nil,
+		dedup:     false,
+		strategy:  nil,
+		blurHash:  false,
+	}
+	for _, opt := range options {
+		opt(option)
+	}
+	return option
+}
+
 // WithQueue sets the queue for managing files that failed to delete.
 // Files in the queue must be deleted manually later.
 func WithQueue(queue cache.Queue) Option {
@@ -25,16 +57,43 @@ func WithQueue(queue cache.Queue) Option {
 }
 
 // WithNumbered enables numeric file naming.
+// It overrides any previously set name strategy.
 func WithNumbered() Option {
 	return func(o *option) {
 		o.numbered = true
+		o.strategy = nil
 	}
 }
 
-// WithTimestamped enables timestamp-based file naming.
+// WithTimestamped enables timestamp-based file naming (default).
+// It overrides any previously set name strategy.
 func WithTimestamped() Option {
 	return func(o *option) {
 		o.numbered = false
+		o.strategy = nil
+	}
+}
+
+// WithNameStrategy sets a custom file naming strategy (e.g. UUIDStrategy).
+// Strategy receives sanitized original file name and must generate unique names.
+// It overrides numbered and timestamped naming, the last naming option applied wins.
+// Content based naming of WithDedup takes precedence over strategy.
+func WithNameStrategy(strategy NameStrategy) Option {
+	return func(o *option) {
+		if strategy != nil {
+			o.numbered = false
+			o.strategy = strategy
+		}
+	}
+}
+
+// WithDedup enables content based file naming using SHA-256 checksum.
+// Save skips writing if a file with the same content already exists.
+// Stored files may be shared by several uploads, so Delete never removes them and Move copies them.
+// Remove unreferenced files with application level reference counting.
+func WithDedup() Option {
+	return func(o *option) {
+		o.dedup = true
 	}
 }
 
@@ -45,3 +104,60 @@ func WithPrefix(prefix string) Option {
 		o.prefix = strings.TrimSpace(prefix)
 	}
 }
+
+// WithASCIINames enables transliteration of file names to ASCII characters.
+func WithASCIINames() Option {
+	return func(o *option) {
+		o.ascii = true
+	}
+}
+
+// WithMimeLimit sets the maximum number of bytes read for MIME detection.
+// By default mimetype package limit is used.
+func WithMimeLimit(n int) Option {
+	return func(o *option) {
+		if n > 0 {
+			o.mimeLimit = int64(n)
+		}
+	}
+}
+
+// WithScanner sets a scanner (e.g. antivirus) to check file content before save.
+// Scanner receives a seekable reader and save is aborted if scanner returns error.
+func WithScanner(scanner func(io.Reader) error) Option {
+	return func(o *option) {
+		o.scanner = scanner
+	}
+}
+
+// WithProgress sets a callback to report save progress.
+// Callback is called every 32KB written and on completion.
+func WithProgress(callback func(written, total int64)) Option {
+	return func(o *option) {
+		o.progress = callback
+	}
+}
+
+// WithThumbnail enables thumbnail generation for image uploads.
+// Thumbnail is resized to fit into width and height with aspect ratio preserved.
+// Thumbnail file name is generated by appending suffix before extension.
+// Corrupt images and images larger than 40 megapixels are skipped without thumbnail.
+func WithThumbnail(width, height int, suffix string) Option {
+	suffix = strings.TrimSpace(suffix)
+	return func(o *option) {
+		if (width > 0 || height > 0) && suffix != "" {
+			o.thumbWidth = max(width, 0)
+			o.thumbHeight = max(height, 0)
+			o.thumbSuffix = suffix
+		}
+	}
+}
+
+// WithBlurHash enables BlurHash placeholder generation for image uploads on save.
+// Image is decoded after save, use BlurHash to get generated placeholder.
+// Corrupt images and images larger than 40 megapixels are skipped without placeholder.
+func WithBlurHash() Option {
+	return func(o *option) {
+		o.blurHash = true
+	}
+}
@@ -0,0 +1,74 @@
+package uploader
+
+import (
+	"io"
+	"mime/multipart"
+	"os"
+)
+
+// progressWriter is a writer that reports written bytes to callback.
+type progressWriter struct {
+	w        io.Writer
+	written  int64
+	total    int64
+	callback func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.callback(p.written, p.total)
+	return n, err
+}
+
+// saveFile copies file content to dest in 32KB chunks and reports progress if callback is not nil.
+// Multipart temp files are moved to dest if possible and copied on failure (e.g. cross device).
+func saveFile(file *multipart.FileHeader, dest string, callback func(written, total int64)) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+
+	// Move multipart temp file if possible
+	if f, ok := src.(*os.File); ok {
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		if rename(f.Name(), dest) == nil {
+			if callback != nil {
+				callback(file.Size, file.Size)
+			}
+			return nil
+		}
+
+		// Reopen to copy across devices
+		if src, err = file.Open(); err != nil {
+			return err
+		}
+	}
+	defer src.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var w io.Writer = out
+	if callback != nil {
+		w = &progressWriter{w: out, total: file.Size, callback: callback}
+	}
+
+	written, err := io.CopyBuffer(w, src, make([]byte, 32*1024))
+	if err != nil {
+		return err
+	}
+
+	// Report empty file completion
+	if written == 0 && callback != nil {
+		callback(0, file.Size)
+	}
+
+	return out.Close()
+}
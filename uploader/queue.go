@@ -0,0 +1,49 @@
+package uploader
+
+import (
+	"errors"
+	"os"
+
+	"github.com/go-universal/cache"
+)
+
+// ProcessDeleteQueue retries deletion of files queued by SafeDelete.
+// Missing files are treated as deleted and failed files are pushed back to the queue.
+// It returns the number of deleted and re-queued failed files.
+func ProcessDeleteQueue(queue cache.Queue) (deleted, failed int, err error) {
+	if queue == nil {
+		return 0, 0, nil
+	}
+
+	length, err := queue.Length()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	failures := make([]string, 0)
+	for range length {
+		path, err := queue.Pull()
+		if err != nil {
+			return deleted, failed, err
+		} else if path == nil {
+			break
+		}
+
+		err = os.Remove(*path)
+		if err == nil || errors.Is(err, os.ErrNotExist) {
+			deleted++
+		} else {
+			failures = append(failures, *path)
+		}
+	}
+
+	// Requeue failed files
+	for _, path := range failures {
+		if err := queue.Push(path); err != nil {
+			return deleted, failed, err
+		}
+		failed++
+	}
+
+	return deleted, failed, nil
+}
@@ -0,0 +1,37 @@
+package uploader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-universal/http/internal/testcache"
+)
+
+func TestProcessDeleteQueue(t *testing.T) {
+	root := t.TempDir()
+	existing := writeFile(t, "existing.txt", []byte("content"))
+	missing := filepath.Join(root, "missing.txt")
+
+	// Non-empty directory can not be removed
+	busy := filepath.Join(root, "busy")
+	if err := os.MkdirAll(filepath.Join(busy, "child"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	queue := testcache.NewQueue(existing, missing, busy)
+	deleted, failed, err := ProcessDeleteQueue(queue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 || failed != 1 {
+		t.Errorf("deleted, failed = %d, %d, want 2, 1", deleted, failed)
+	}
+	if _, err := os.Stat(existing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("existing file not removed: %v", err)
+	}
+	if items := queue.Items(); len(items) != 1 || items[0] != busy {
+		t.Errorf("queue = %v, want failed path requeued", items)
+	}
+}
@@ -0,0 +1,46 @@
+package uploader
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// sanitizeName removes path elements and unsafe characters from file name,
+// so the stored file can never escape the root directory.
+// Non-ASCII characters are transliterated or removed if ascii is true.
+func sanitizeName(name string, ascii bool) string {
+	// Strip directories
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	// Decompose accented characters
+	if ascii {
+		name = norm.NFKD.String(name)
+	}
+
+	// Remove control, format and separator characters
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\':
+			return -1
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			return -1
+		case ascii && unicode.Is(unicode.Mn, r):
+			return -1
+		case ascii && r > unicode.MaxASCII:
+			return '_'
+		default:
+			return r
+		}
+	}, name)
+
+	// Remove dot segments and hidden file prefix
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "file"
+	}
+
+	return name
+}
@@ -0,0 +1,54 @@
+package uploader
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		ascii    bool
+		expected string
+	}{
+		{"photo.png", false, "photo.png"},
+		{"../../etc/passwd", false, "passwd"},
+		{"..\\..\\boot.ini", false, "boot.ini"},
+		{"a\x00.png", false, "a.png"},
+		{"a\u200b.png", false, "a.png"},
+		{".hidden", false, "hidden"},
+		{"..", false, "file"},
+		{"", false, "file"},
+		{"résumé.pdf", false, "résumé.pdf"},
+		{"résumé.pdf", true, "resume.pdf"},
+		{"файл.txt", true, "____.txt"},
+	}
+
+	for _, tt := range tests {
+		if name := sanitizeName(tt.name, tt.ascii); name != tt.expected {
+			t.Errorf("sanitizeName(%q, %v) = %q, want %q", tt.name, tt.ascii, name, tt.expected)
+		}
+	}
+}
+
+func TestSavedPathUnderRoot(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"../../etc/passwd", "a\x00.png", "/abs/file.txt"} {
+		file := newFileHeader(t, "file", []byte("content"))
+		file.Filename = name
+
+		u, err := NewUploader(root, file, WithNumbered())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := u.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		rel, err := filepath.Rel(root, u.Path())
+		if err != nil || strings.HasPrefix(rel, "..") || strings.ContainsAny(rel, "/\\\x00") {
+			t.Errorf("%q saved outside root: %q", name, u.Path())
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package uploader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sign generates HMAC-SHA256 signature of path and expiry.
+// Path is cleaned, so escaped and normalized forms of same path have same signature.
+func sign(p string, expires int64, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(path.Clean("/"+p) + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signURL appends expires and signature query params to raw URL.
+// Returns empty string if secret is empty or URL can not be parsed.
+func signURL(raw string, ttl time.Duration, secret []byte) string {
+	if len(secret) == 0 {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	expires := time.Now().Add(ttl).Unix()
+	query := u.Query()
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", sign(u.Path, expires, secret))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// VerifySignature checks the signature of URL generated by SignedURL.
+// Returns false for missing, expired or tampered signatures and empty secret.
+func VerifySignature(c *fiber.Ctx, secret []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	signature := strings.ToLower(c.Query("signature"))
+	expected := sign(string(c.Request().URI().Path()), expires, secret)
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
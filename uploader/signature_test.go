@@ -0,0 +1,70 @@
+package uploader
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSignature(t *testing.T) {
+	secret := []byte("secret")
+	app := fiber.New()
+	app.Get("/*", func(c *fiber.Ctx) error {
+		if !VerifySignature(c, secret) {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	signed := func(raw string, ttl time.Duration) string {
+		return signURL(raw, ttl, secret)
+	}
+
+	valid := signed("/uploads/photo.jpg", time.Minute)
+	spaced := signed("/uploads/my photo.jpg", time.Minute)
+	tests := []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{"valid", valid, fiber.StatusOK},
+		{"escaped path", spaced, fiber.StatusOK},
+		{"dot segments", strings.Replace(valid, "/uploads/", "/uploads/./", 1), fiber.StatusOK},
+		{"expired", signed("/uploads/photo.jpg", -time.Minute), fiber.StatusForbidden},
+		{"tampered path", strings.Replace(valid, "photo", "other", 1), fiber.StatusForbidden},
+		{"tampered signature", valid[:len(valid)-1] + flip(valid[len(valid)-1:]), fiber.StatusForbidden},
+		{"tampered expires", strings.Replace(valid, "expires=", "expires=9", 1), fiber.StatusForbidden},
+		{"missing", "/uploads/photo.jpg", fiber.StatusForbidden},
+		{"wrong secret", signURL("/uploads/photo.jpg", time.Minute, []byte("other")), fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestSignURLInvalid(t *testing.T) {
+	if u := signURL("http://[::1", time.Minute, []byte("secret")); u != "" {
+		t.Errorf("invalid URL signed: %q", u)
+	}
+	if u := signURL("/uploads/photo.jpg", time.Minute, nil); u != "" {
+		t.Errorf("URL signed with empty secret: %q", u)
+	}
+}
+
+// flip returns a different hex digit.
+func flip(digit string) string {
+	if digit == "0" {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,17 @@
+package uploader
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// NameStrategy is a function type that generates stored file name from sanitized original file name.
+// Generated name is sanitized too, directory elements and unsafe characters are removed.
+type NameStrategy func(original string) string
+
+// UUIDStrategy generates a random UUID file name keeping original file extension.
+func UUIDStrategy(original string) string {
+	return uuid.NewString() + strings.ToLower(filepath.Ext(original))
+}
@@ -0,0 +1,108 @@
+package uploader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUUIDStrategy(t *testing.T) {
+	if name := UUIDStrategy("Photo.JPG"); !strings.HasSuffix(name, ".jpg") || len(name) != 36+4 {
+		t.Errorf("UUIDStrategy() = %q, want uuid with .jpg extension", name)
+	}
+
+	// Concurrent names never collide
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	names := make(map[string]struct{})
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				name := UUIDStrategy("file.txt")
+				mutex.Lock()
+				names[name] = struct{}{}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(names) != 50*100 {
+		t.Errorf("unique names = %d, want %d", len(names), 50*100)
+	}
+}
+
+func TestGenerateNameSanitizesStrategy(t *testing.T) {
+	tests := map[string]string{
+		"../../etc/passwd":  "passwd",
+		"/abs/name.txt":     "name.txt",
+		"..\\..\\win.ini":   "win.ini",
+		"..":                "file",
+		"":                  "file",
+		"sub/dir/photo.jpg": "photo.jpg",
+	}
+
+	for generated, expected := range tests {
+		option := &option{strategy: func(string) string { return generated }}
+		name, err := generateName(t.TempDir(), "original.txt", option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != expected {
+			t.Errorf("strategy %q name = %q, want %q", generated, name, expected)
+		}
+	}
+}
+
+func TestConcurrentSave(t *testing.T) {
+	const count = 50
+	root := t.TempDir()
+
+	// Headers are created before goroutines, t.Fatal must run on test goroutine
+	uploaders := make([]Uploader, count)
+	for i := range count {
+		content := []byte(fmt.Sprintf("content %d", i))
+		u, err := NewUploader(root, newFileHeader(t, "file.txt", content), WithNameStrategy(UUIDStrategy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		uploaders[i] = u
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, count)
+	for i, u := range uploaders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = u.Save()
+		}()
+	}
+	wg.Wait()
+
+	paths := make(map[string]struct{})
+	for i, u := range uploaders {
+		if errs[i] != nil {
+			t.Fatalf("upload %d: %v", i, errs[i])
+		}
+		paths[u.Path()] = struct{}{}
+
+		// Stored content must belong to its own upload
+		data, err := os.ReadFile(u.Path())
+		if err != nil {
+			t.Fatal(err)
+		} else if expected := fmt.Sprintf("content %d", i); string(data) != expected {
+			t.Errorf("upload %d content = %q, want %q", i, data, expected)
+		}
+	}
+	if len(paths) != count {
+		t.Errorf("unique paths = %d, want %d", len(paths), count)
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != count {
+		t.Errorf("stored files = %d, want %d", len(entries), count)
+	}
+}
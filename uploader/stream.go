@@ -0,0 +1,235 @@
+package uploader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-universal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/inhies/go-bytesize"
+)
+
+const (
+	maxStreamValue  = 1 << 20  // maxStreamValue is the maximum size of non-file form value read by Stream.
+	maxStreamValues = 10 << 20 // maxStreamValues is the maximum total size of non-file form values read by Stream.
+	maxStreamFields = 1000     // maxStreamFields is the maximum number of non-file form values read by Stream.
+)
+
+// StreamPart is an interface that defines methods for handling a streamed multipart file part.
+// Part content can be read only once, so Save must be called inside Stream handler.
+type StreamPart interface {
+	// Field returns the form field name of the part.
+	Field() string
+
+	// Filename returns the client side file name.
+	Filename() string
+
+	// ValidateMime checks if the part MIME type is among the allowed types.
+	ValidateMime(mimes ...string) (bool, error)
+
+	// MIME returns the part MIME type detected from content head.
+	MIME() (string, error)
+
+	// Size returns the number of bytes written to storage by Save.
+	Size() int64
+
+	// Path returns the file path where the part is stored.
+	Path() string
+
+	// URL returns the URL where the stored part can be accessed.
+	URL() string
+
+	// Save streams the part content to storage.
+	// Use B, KB, MB, GB for max size string. Partially written file is removed if content exceeds max size.
+	Save(max string) error
+
+	// Delete removes the stored part.
+	Delete() error
+}
+
+type streamPart struct {
+	opt    option
+	part   *multipart.Part
+	reader *bufio.Reader
+	name   string
+	root   string
+	size   int64
+	saved  bool
+}
+
+// Stream consumes multipart request body part by part and calls handler for each file part
+// without buffering whole upload in memory. Non-file form values are returned, limited to 1MB each,
+// 10MB in total and 1000 values.
+// Content-based naming (WithDedup), scanner, progress and thumbnail options are not supported.
+//
+// Request body must not be parsed before streaming (e.g. by FormFile, FormValue or BodyParser).
+// Enable fiber.Config.StreamRequestBody to stream body from connection, otherwise buffered body is used.
+func Stream(c *fiber.Ctx, root string, handler func(part StreamPart) error, options ...Option) (map[string][]string, error) {
+	option := newOption(options...)
+	root = strings.TrimSpace(root)
+
+	// Resolve reader
+	boundary := string(c.Request().Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, errors.New("request is not multipart form")
+	}
+
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Request().Body())
+	}
+
+	// Read parts
+	values := make(map[string][]string)
+	fields, total := 0, 0
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return values, nil
+		} else if err != nil {
+			return values, err
+		}
+
+		// Read form value
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxStreamValue+1))
+			if err != nil {
+				return values, err
+			} else if len(value) > maxStreamValue {
+				return values, fmt.Errorf("%s form value too large", part.FormName())
+			}
+
+			fields++
+			total += len(value)
+			if fields > maxStreamFields {
+				return values, errors.New("too many form values")
+			} else if total > maxStreamValues {
+				return values, errors.New("form values too large")
+			}
+
+			values[part.FormName()] = append(values[part.FormName()], string(value))
+			continue
+		}
+
+		// Handle file part
+		name, err := generateName(root, sanitizeName(part.FileName(), option.ascii), option)
+		if err != nil {
+			return values, err
+		}
+
+		head := 3072
+		if option.mimeLimit > 0 {
+			head = int(option.mimeLimit)
+		}
+
+		err = handler(&streamPart{
+			opt:    *option,
+			part:   part,
+			reader: bufio.NewReaderSize(part, head),
+			name:   name,
+			root:   root,
+		})
+		if err != nil {
+			return values, err
+		}
+	}
+}
+
+func (p *streamPart) Field() string {
+	return p.part.FormName()
+}
+
+func (p *streamPart) Filename() string {
+	return p.part.FileName()
+}
+
+func (p *streamPart) ValidateMime(mimes ...string) (bool, error) {
+	mime, err := p.MIME()
+	if err != nil {
+		return false, err
+	}
+
+	return mimetype.EqualsAny(mime, mimes...), nil
+}
+
+func (p *streamPart) MIME() (string, error) {
+	head, err := p.reader.Peek(p.reader.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+
+	return mimetype.Detect(head).String(), nil
+}
+
+func (p *streamPart) Size() int64 {
+	return p.size
+}
+
+func (p *streamPart) Path() string {
+	return utils.NormalizePath(p.root, p.name)
+}
+
+func (p *streamPart) URL() string {
+	return utils.AbsoluteURL(p.opt.prefix, p.Path())
+}
+
+func (p *streamPart) Save(max string) error {
+	// Skip saved
+	if p.saved {
+		return nil
+	}
+
+	// Parse max string
+	maxSize, err := bytesize.Parse(max)
+	if err != nil {
+		return err
+	}
+
+	// Create file
+	dest := p.Path()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s file exists", dest)
+	} else if err != nil {
+		return err
+	}
+
+	// Stream content
+	written, err := io.Copy(out, io.LimitReader(p.reader, int64(maxSize)+1))
+	if err == nil && written > int64(maxSize) {
+		err = fmt.Errorf("file size exceeds %s", max)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dest)
+		return err
+	}
+
+	p.size = written
+	p.saved = true
+	return nil
+}
+
+func (p *streamPart) Delete() error {
+	// Skip not saved
+	if !p.saved {
+		return nil
+	}
+
+	err := os.Remove(p.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,113 @@
+package uploader
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// zeroReader is an endless reader of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// newStreamCtx creates fiber context of multipart request streaming body.
+func newStreamCtx(t *testing.T, boundary string, body io.Reader) *fiber.Ctx {
+	t.Helper()
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.SetContentType(fiber.MIMEMultipartForm + "; boundary=" + boundary)
+	ctx.Request.SetBodyStream(body, -1)
+
+	app := fiber.New(fiber.Config{StreamRequestBody: true})
+	c := app.AcquireCtx(ctx)
+	t.Cleanup(func() { app.ReleaseCtx(c) })
+	return c
+}
+
+func TestStreamLargePart(t *testing.T) {
+	const size = 64 << 20
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	body := io.MultiReader(
+		strings.NewReader("--"+boundary+"\r\n"+
+			"Content-Disposition: form-data; name=\"title\"\r\n\r\nlarge\r\n"+
+			"--"+boundary+"\r\n"+
+			"Content-Disposition: form-data; name=\"file\"; filename=\"large.bin\"\r\n"+
+			"Content-Type: application/octet-stream\r\n\r\n"),
+		io.LimitReader(zeroReader{}, size),
+		strings.NewReader("\r\n--"+boundary+"--\r\n"),
+	)
+
+	c := newStreamCtx(t, boundary, body)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var path string
+	var written int64
+	values, err := Stream(c, t.TempDir(), func(part StreamPart) error {
+		if err := part.Save("100MB"); err != nil {
+			return err
+		}
+		path, written = part.Path(), part.Size()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > size/4 {
+		t.Errorf("allocated %d bytes streaming %d bytes part", allocated, size)
+	}
+
+	if title := values["title"]; len(title) != 1 || title[0] != "large" {
+		t.Errorf("values = %v, want title value", values)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != size || written != size {
+		t.Errorf("saved file = %v, %v, size %d, want %d bytes", info, err, written, size)
+	}
+}
+
+func TestStreamValueLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		count  int
+		size   int
+		failed bool
+	}{
+		{"within limits", 10, 1024, false},
+		{"too many values", maxStreamFields + 1, 1, true},
+		{"values too large", maxStreamValues/maxStreamValue + 1, maxStreamValue, true},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		value := strings.Repeat("x", tt.size)
+		for i := range tt.count {
+			w.WriteField("field"+strconv.Itoa(i), value)
+		}
+		w.Close()
+
+		_, err := Stream(newStreamCtx(t, w.Boundary(), &buf), t.TempDir(), func(part StreamPart) error {
+			return nil
+		})
+		if (err != nil) != tt.failed {
+			t.Errorf("%s: error = %v, want failed %v", tt.name, err, tt.failed)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package uploader
+
+import (
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// maxImagePixels is the maximum decoded image size to protect against decompression bombs.
+const maxImagePixels = 40_000_000
+
+// thumbnailName generates thumbnail file name by appending suffix before extension.
+func thumbnailName(name, suffix string) string {
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + suffix + ext
+}
+
+// thumbnailSize calculates thumbnail dimension that fits into width and height box.
+// Aspect ratio is preserved and image is never upscaled.
+func thumbnailSize(w, h, width, height int) (int, int) {
+	scale := 1.0
+	if width > 0 && w > width {
+		scale = float64(width) / float64(w)
+	}
+	if height > 0 && h > height && float64(height)/float64(h) < scale {
+		scale = float64(height) / float64(h)
+	}
+
+	return max(int(float64(w)*scale), 1), max(int(float64(h)*scale), 1)
+}
+
+// decodeImage decodes src image if its header is valid and size is within maxImagePixels.
+// Returns nil image for unsupported, corrupt or oversized images.
+func decodeImage(src string) (image.Image, string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	// Check dimension before allocating pixels
+	config, _, err := image.DecodeConfig(f)
+	if err != nil || int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return nil, "", nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, "", err
+	}
+
+	img, format, err := image.Decode(f)
+	if err != nil {
+		return nil, "", nil
+	}
+
+	return img, format, nil
+}
+
+// createThumbnail writes resized copy of src image to dest.
+// Returns false if src is not a supported, valid or small enough image.
+func createThumbnail(src, dest string, width, height int) (bool, error) {
+	// Decode source image
+	img, format, err := decodeImage(src)
+	if err != nil || img == nil {
+		return false, err
+	}
+
+	// Resize
+	bounds := img.Bounds()
+	w, h := thumbnailSize(bounds.Dx(), bounds.Dy(), width, height)
+	thumb := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, bounds, draw.Over, nil)
+
+	// Encode thumbnail
+	out, err := os.Create(dest)
+	if err != nil {
+		return false, err
+	}
+	defer out.Close()
+
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(out, thumb, nil)
+	case "gif":
+		err = gif.Encode(out, thumb, nil)
+	default:
+		err = png.Encode(out, thumb)
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(dest)
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,150 @@
+package uploader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePNG writes w x h png image and returns its path.
+func writePNG(t *testing.T, w, h int) string {
+	t.Helper()
+
+	content, err := pngBytes(t, w, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeFile(t, "image.png", content)
+}
+
+// pngBytes encodes w x h png image.
+func pngBytes(t *testing.T, w, h int) ([]byte, error) {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := range w {
+		for y := range h {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), err
+}
+
+// writeFile writes content to temp file and returns its path.
+func writeFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// bombPNG returns png header declaring w x h dimension without pixel data.
+func bombPNG(w, h uint32) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	data := buf.Bytes()
+
+	// Patch IHDR dimension and checksum
+	binary.BigEndian.PutUint32(data[16:], w)
+	binary.BigEndian.PutUint32(data[20:], h)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestThumbnailSize(t *testing.T) {
+	tests := []struct {
+		w, h, width, height int
+		ew, eh              int
+	}{
+		{400, 200, 100, 100, 100, 50},
+		{200, 400, 100, 100, 50, 100},
+		{400, 200, 100, 0, 100, 50},
+		{50, 50, 100, 100, 50, 50},
+		{1000, 1, 10, 10, 10, 1},
+	}
+
+	for _, tt := range tests {
+		w, h := thumbnailSize(tt.w, tt.h, tt.width, tt.height)
+		if w != tt.ew || h != tt.eh {
+			t.Errorf("thumbnailSize(%d, %d, %d, %d) = %d, %d, want %d, %d",
+				tt.w, tt.h, tt.width, tt.height, w, h, tt.ew, tt.eh)
+		}
+	}
+}
+
+func TestCreateThumbnail(t *testing.T) {
+	src := writePNG(t, 64, 32)
+	dest := filepath.Join(t.TempDir(), "thumb.png")
+
+	ok, err := createThumbnail(src, dest, 16, 16)
+	if err != nil || !ok {
+		t.Fatalf("createThumbnail() = %v, %v, want true, nil", ok, err)
+	}
+
+	f, err := os.Open(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	config, err := png.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Width != 16 || config.Height != 8 {
+		t.Errorf("thumbnail size = %dx%d, want 16x8", config.Width, config.Height)
+	}
+}
+
+func TestCreateThumbnailSkipped(t *testing.T) {
+	tests := map[string][]byte{
+		"text":      []byte("plain text file"),
+		"corrupt":   append(bombPNG(10, 10)[:40], []byte("garbage")...),
+		"oversized": bombPNG(100_000, 100_000),
+	}
+
+	for name, content := range tests {
+		src := writeFile(t, "image.png", content)
+		dest := filepath.Join(t.TempDir(), "thumb.png")
+
+		ok, err := createThumbnail(src, dest, 16, 16)
+		if ok || err != nil {
+			t.Errorf("%s: createThumbnail() = %v, %v, want false, nil", name, ok, err)
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Errorf("%s: thumbnail file created", name)
+		}
+
+		hash, err := createBlurHash(src)
+		if hash != "" || err != nil {
+			t.Errorf("%s: createBlurHash() = %q, %v, want empty, nil", name, hash, err)
+		}
+	}
+}
+
+func TestCreateBlurHash(t *testing.T) {
+	hash, err := createBlurHash(writePNG(t, 64, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Size flag + max AC + DC + 11 AC components
+	if len(hash) != 1+1+4+11*2 {
+		t.Errorf("hash length = %d, want 28", len(hash))
+	}
+	if hash[0] != blurHashChars[3+2*9] {
+		t.Errorf("size flag = %c, want %c", hash[0], blurHashChars[3+2*9])
+	}
+}
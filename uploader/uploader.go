@@ -1,11 +1,17 @@
 package uploader
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"image"
+	"io"
 	"mime/multipart"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/go-universal/utils"
@@ -19,27 +25,88 @@ type Uploader interface {
 	// IsNil checks if the uploader is nil.
 	IsNil() bool
 
+	// NotEmpty checks if the file is not a zero-byte file.
+	// Returns false for nil file, use IsNil to distinguish missing file from empty file.
+	NotEmpty() (bool, error)
+
 	// ValidateSize checks if the file size is within the specified limit.
 	// Use B, KB, MB, GB for size string
 	ValidateSize(min, max string) (bool, error)
 
 	// ValidateMime checks if the file MIME type is among the allowed types.
+	// File content is sniffed from start regardless of previous reads and saved content is not affected.
 	ValidateMime(mimes ...string) (bool, error)
 
+	// ValidateImageBomb checks if the image pixel count (width * height) is within the max limit.
+	// Only image header is read and pixels are not decoded. Non-image (or unsupported image format)
+	// files are considered valid, use ValidateMime to restrict file types.
+	ValidateImageBomb(maxPixels int64) (bool, error)
+
+	// MIME returns the detected file MIME type.
+	// Detection result is cached and reused by other methods.
+	MIME() (string, error)
+
+	// Bytes reads the file content into memory up to max size.
+	// Use B, KB, MB, GB for size string. Returns error if file is larger than max size.
+	Bytes(max string) ([]byte, error)
+
 	// Path returns the file path where the uploaded file is stored.
 	Path() string
 
 	// URL returns the URL where the uploaded file can be accessed.
 	URL() string
 
+	// SignedURL returns the file URL signed with secret and valid for ttl.
+	// Use VerifySignature to validate signed URL requests.
+	// Returns empty string if secret is empty or file URL can not be parsed.
+	SignedURL(ttl time.Duration, secret []byte) string
+
 	// Save stores the uploaded file.
 	Save() error
 
+	// Move relocates the saved file and its thumbnail to new root directory.
+	// Path and URL reflect the new location after move.
+	// Content addressed files of WithDedup are copied and kept in old root, since other uploads may share them.
+	Move(root string) error
+
 	// Delete removes the uploaded file.
+	// Shared content addressed files of WithDedup are never removed.
 	Delete() error
 
 	// SafeDelete removes the uploaded file safely, queueing the file name on failure.
 	SafeDelete()
+
+	// ThumbnailPath returns the thumbnail path of the uploaded image.
+	// Returns empty string if no thumbnail generated.
+	ThumbnailPath() string
+
+	// ThumbnailURL returns the thumbnail URL of the uploaded image.
+	// Returns empty string if no thumbnail generated.
+	ThumbnailURL() string
+
+	// BlurHash returns the BlurHash placeholder of the uploaded image.
+	// Returns empty string if no placeholder generated.
+	BlurHash() string
+
+	// Deduplicated reports whether Save skipped writing because
+	// a file with the same content already exists.
+	Deduplicated() bool
+
+	// Metadata returns the uploaded file information.
+	// It can be called before or after Save.
+	Metadata() (UploadInfo, error)
+}
+
+// UploadInfo holds the information of an uploaded file.
+type UploadInfo struct {
+	OriginalName string            // Client side file name.
+	StoredName   string            // Generated file name on storage.
+	Path         string            // File path on storage.
+	URL          string            // File access URL.
+	Size         bytesize.ByteSize // File size.
+	MIME         string            // Detected file MIME type.
+	Checksum     string            // SHA-256 checksum of file content in hex.
+	Deduplicated bool              // Whether an existing file with same content reused.
 }
 
 type uploader struct {
@@ -47,45 +114,50 @@ type uploader struct {
 	file  *multipart.FileHeader
 	name  string
 	root  string
+	mime  *mimetype.MIME
+	hash  string
+	thumb string
+	blur  string
 	saved bool
+	dedup bool
 }
 
 // NewUploader creates a new Uploader instance with the given root directory and file header.
+// With WithDedup option, the whole upload is read and hashed here to generate the stored name.
 func NewUploader(root string, file *multipart.FileHeader, options ...Option) (Uploader, error) {
 	// Initialize and normalize
 	var name string
 	root = strings.TrimSpace(root)
 
 	// Create option with default values.
-	option := &option{
-		queue:    nil,
-		numbered: false,
-		prefix:   "",
-	}
-	for _, opt := range options {
-		opt(option)
+	option := newOption(options...)
+
+	// Create the uploader instance.
+	u := &uploader{
+		opt:  *option,
+		file: file,
+		root: root,
 	}
 
 	// Generate file name
 	if file != nil {
-		if option.numbered {
-			n, err := utils.NumberedFile(root, file.Filename)
+		original := sanitizeName(file.Filename, option.ascii)
+		if option.dedup {
+			checksum, err := u.checksum()
 			if err != nil {
 				return nil, err
 			}
-			name = n
+			name = checksum + strings.ToLower(filepath.Ext(original))
 		} else {
-			name = utils.TimestampedFile(file.Filename)
+			n, err := generateName(root, original, option)
+			if err != nil {
+				return nil, err
+			}
+			name = n
 		}
 	}
 
-	// Create and return the uploader instance.
-	u := &uploader{
-		opt:  *option,
-		file: file,
-		name: name,
-		root: root,
-	}
+	u.name = name
 	return u, nil
 }
 
@@ -114,10 +186,39 @@ func FiberFile(c *fiber.Ctx, name string) (*multipart.FileHeader, error) {
 	return f, err
 }
 
+// ValidateTotalSize checks if the total size of files is within the max limit.
+// Nil files are ignored. Use B, KB, MB, GB for size string.
+func ValidateTotalSize(max string, files ...*multipart.FileHeader) (bool, error) {
+	// Parse max string
+	maxSize, err := bytesize.Parse(max)
+	if err != nil {
+		return false, err
+	}
+
+	// Sum sizes
+	var total int64
+	for _, file := range files {
+		if file != nil {
+			total += file.Size
+		}
+	}
+
+	return total <= int64(maxSize), nil
+}
+
 func (u *uploader) IsNil() bool {
 	return u.file == nil
 }
 
+func (u *uploader) NotEmpty() (bool, error) {
+	// Invalidate nil file
+	if u.IsNil() {
+		return false, nil
+	}
+
+	return u.file.Size > 0, nil
+}
+
 func (u *uploader) ValidateSize(min, max string) (bool, error) {
 	// Invalidate nil file
 	if u.IsNil() {
@@ -147,20 +248,80 @@ func (u *uploader) ValidateMime(mimes ...string) (bool, error) {
 		return false, nil
 	}
 
+	// Validate mime
+	mime, err := u.detect()
+	if err != nil {
+		return false, err
+	}
+
+	return mimetype.EqualsAny(mime.String(), mimes...), nil
+}
+
+func (u *uploader) ValidateImageBomb(maxPixels int64) (bool, error) {
+	// Invalidate nil file
+	if u.IsNil() {
+		return false, nil
+	}
+
 	// Read file content
-	f, err := u.file.Open()
+	f, err := u.open()
 	if err != nil {
 		return false, err
 	}
 	defer f.Close()
 
-	// Validate mime
-	mime, err := mimetype.DetectReader(f)
-	if err != nil {
+	// Read image dimensions
+	config, _, err := image.DecodeConfig(f)
+	if errors.Is(err, image.ErrFormat) {
+		return true, nil
+	} else if err != nil {
 		return false, err
 	}
 
-	return mimetype.EqualsAny(mime.String(), mimes...), nil
+	return int64(config.Width)*int64(config.Height) <= maxPixels, nil
+}
+
+func (u *uploader) MIME() (string, error) {
+	// Skip nil file
+	if u.IsNil() {
+		return "", nil
+	}
+
+	mime, err := u.detect()
+	if err != nil {
+		return "", err
+	}
+
+	return mime.String(), nil
+}
+
+func (u *uploader) Bytes(max string) ([]byte, error) {
+	// Skip nil file
+	if u.IsNil() {
+		return nil, nil
+	}
+
+	// Parse max string
+	maxSize, err := bytesize.Parse(max)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read file content
+	f, err := u.open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	} else if int64(len(content)) > int64(maxSize) {
+		return nil, fmt.Errorf("file size exceeds %s", max)
+	}
+
+	return content, nil
 }
 
 func (u *uploader) Path() string {
@@ -181,6 +342,15 @@ func (u *uploader) URL() string {
 	return utils.AbsoluteURL(u.opt.prefix, u.Path())
 }
 
+func (u *uploader) SignedURL(ttl time.Duration, secret []byte) string {
+	// Skip nil file
+	if u.IsNil() {
+		return ""
+	}
+
+	return signURL(u.URL(), ttl, secret)
+}
+
 func (u *uploader) Save() error {
 	// Skip nil file or saved
 	if u.IsNil() || u.saved {
@@ -193,26 +363,118 @@ func (u *uploader) Save() error {
 	exists, err := utils.FileExists(dest)
 	if err != nil {
 		return err
+	} else if exists && u.opt.dedup {
+		u.saved = true
+		u.dedup = true
+		if u.opt.blurHash {
+			if u.blur, err = createBlurHash(dest); err != nil {
+				return err
+			}
+		}
+		if u.opt.thumbSuffix != "" {
+			name := thumbnailName(u.name, u.opt.thumbSuffix)
+			if ok, _ := utils.FileExists(utils.NormalizePath(u.root, name)); ok {
+				u.thumb = name
+			}
+		}
+		return nil
 	} else if exists {
 		return fmt.Errorf("%s file exists", dest)
 	}
 
+	// Scan content
+	if u.opt.scanner != nil {
+		if err := u.scan(); err != nil {
+			return err
+		}
+	}
+
 	// Save
-	err = fasthttp.SaveMultipartFile(u.file, dest)
-	if err != nil {
+	if err := saveFile(u.file, dest, u.opt.progress); err != nil {
 		return err
 	}
 
 	u.saved = true
+
+	// Generate placeholder
+	if u.opt.blurHash {
+		if u.blur, err = createBlurHash(dest); err != nil {
+			return err
+		}
+	}
+
+	// Generate thumbnail
+	if u.opt.thumbSuffix != "" {
+		name := thumbnailName(u.name, u.opt.thumbSuffix)
+		ok, err := createThumbnail(
+			dest,
+			utils.NormalizePath(u.root, name),
+			u.opt.thumbWidth,
+			u.opt.thumbHeight,
+		)
+		if err != nil {
+			return err
+		} else if ok {
+			u.thumb = name
+		}
+	}
+
+	return nil
+}
+
+func (u *uploader) Move(root string) error {
+	// Skip nil file
+	if u.IsNil() {
+		return nil
+	} else if !u.saved {
+		return errors.New("file not saved")
+	}
+
+	root = strings.TrimSpace(root)
+	dest := utils.NormalizePath(root, u.name)
+	if dest == u.Path() {
+		return nil
+	}
+
+	// Check if exists
+	if exists, err := utils.FileExists(dest); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("%s file exists", dest)
+	}
+
+	// Move file
+	thumb := u.ThumbnailPath()
+	keep := u.opt.dedup
+	if err := moveFile(u.Path(), dest, keep); err != nil {
+		return err
+	}
+
+	u.root = root
+	u.dedup = false
+
+	// Move thumbnail
+	if thumb != "" {
+		return moveFile(thumb, u.ThumbnailPath(), keep)
+	}
+
 	return nil
 }
 
 func (u *uploader) Delete() error {
-	// Skip nil file or not saved
-	if u.IsNil() || !u.saved {
+	// Skip nil file, not saved or content addressed shared file
+	if u.IsNil() || !u.saved || u.opt.dedup {
 		return nil
 	}
 
+	// Delete thumbnail
+	if u.thumb != "" {
+		err := os.Remove(u.ThumbnailPath())
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
 	// Delete
 	err := os.Remove(u.Path())
 	if errors.Is(err, os.ErrNotExist) {
@@ -232,3 +494,157 @@ func (u *uploader) SafeDelete() {
 		u.opt.queue.Push(u.Path())
 	}
 }
+
+func (u *uploader) ThumbnailPath() string {
+	// Skip nil file or no thumbnail
+	if u.IsNil() || u.thumb == "" {
+		return ""
+	}
+
+	return utils.NormalizePath(u.root, u.thumb)
+}
+
+func (u *uploader) ThumbnailURL() string {
+	// Skip nil file or no thumbnail
+	if u.IsNil() || u.thumb == "" {
+		return ""
+	}
+
+	return utils.AbsoluteURL(u.opt.prefix, u.ThumbnailPath())
+}
+
+func (u *uploader) BlurHash() string {
+	return u.blur
+}
+
+func (u *uploader) Deduplicated() bool {
+	return u.dedup
+}
+
+func (u *uploader) Metadata() (UploadInfo, error) {
+	// Skip nil file
+	if u.IsNil() {
+		return UploadInfo{}, nil
+	}
+
+	mime, err := u.detect()
+	if err != nil {
+		return UploadInfo{}, err
+	}
+
+	checksum, err := u.checksum()
+	if err != nil {
+		return UploadInfo{}, err
+	}
+
+	return UploadInfo{
+		OriginalName: u.file.Filename,
+		StoredName:   u.name,
+		Path:         u.Path(),
+		URL:          u.URL(),
+		Size:         bytesize.New(float64(u.file.Size)),
+		MIME:         mime.String(),
+		Checksum:     checksum,
+		Deduplicated: u.dedup,
+	}, nil
+}
+
+// generateName generates stored file name using strategy, numbered or timestamped naming.
+// Strategy result is sanitized, so custom strategies can not escape the root directory.
+func generateName(root, original string, option *option) (string, error) {
+	if option.strategy != nil {
+		return sanitizeName(option.strategy(original), option.ascii), nil
+	} else if option.numbered {
+		return utils.NumberedFile(root, original)
+	}
+	return utils.TimestampedFile(original), nil
+}
+
+// open opens the file content, from storage if saved.
+// Saved multipart temp files are moved and can not be opened from header.
+// Reader is rewound to start, so content read by handlers does not affect result.
+func (u *uploader) open() (io.ReadSeekCloser, error) {
+	var f io.ReadSeekCloser
+	var err error
+	if u.saved {
+		f, err = os.Open(u.Path())
+	} else {
+		f, err = u.file.Open()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// scan passes file content to the configured scanner.
+func (u *uploader) scan() error {
+	f, err := u.file.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return u.opt.scanner(f)
+}
+
+// detect detects file MIME type once and caches the result.
+func (u *uploader) detect() (*mimetype.MIME, error) {
+	if u.mime != nil {
+		return u.mime, nil
+	}
+
+	// Read file content
+	f, err := u.open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Detect mime
+	var r io.Reader = f
+	if u.opt.mimeLimit > 0 {
+		r = io.LimitReader(f, u.opt.mimeLimit)
+	}
+
+	mime, err := mimetype.DetectReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mime = mime
+	return mime, nil
+}
+
+// checksum calculates file content SHA-256 checksum once and caches the result.
+func (u *uploader) checksum() (string, error) {
+	if u.hash != "" {
+		return u.hash, nil
+	}
+
+	// Read file content
+	f, err := u.open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// Hash content
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	u.hash = hex.EncodeToString(h.Sum(nil))
+	return u.hash, nil
+}
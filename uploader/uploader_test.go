@@ -0,0 +1,432 @@
+package uploader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"io"
+	"mime/multipart"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/inhies/go-bytesize"
+)
+
+// newFileHeader creates multipart file header with content.
+func newFileHeader(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	w.Close()
+
+	form, err := multipart.NewReader(&buf, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+func TestDedupDeleteKeepsSharedFile(t *testing.T) {
+	root := t.TempDir()
+	content := []byte("shared content")
+
+	first, err := NewUploader(root, newFileHeader(t, "a.txt", content), WithDedup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := NewUploader(root, newFileHeader(t, "b.txt", content), WithDedup())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if !second.Deduplicated() || second.Path() != first.Path() {
+		t.Fatalf("second upload not deduplicated: %q, %q", second.Path(), first.Path())
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 1 {
+		t.Errorf("stored files = %d, want 1", len(entries))
+	}
+
+	// Original writer must not remove file shared with second upload
+	if err := first.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(second.Path()); err != nil {
+		t.Errorf("shared file removed: %v", err)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	u, err := NewUploader(t.TempDir(), newFileHeader(t, "a.txt", []byte("content")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(u.Path()); !os.IsNotExist(err) {
+		t.Errorf("file not removed: %v", err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	root := t.TempDir()
+	content := []byte("hello world")
+
+	u, err := NewUploader(root, newFileHeader(t, "hello.txt", content), WithPrefix(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := u.Metadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(content)
+	if meta.OriginalName != "hello.txt" ||
+		meta.StoredName == "" ||
+		meta.Path != u.Path() ||
+		meta.URL != u.URL() ||
+		meta.Size != bytesize.New(float64(len(content))) ||
+		!strings.HasPrefix(meta.MIME, "text/plain") ||
+		meta.Checksum != hex.EncodeToString(sum[:]) ||
+		meta.Deduplicated {
+		t.Errorf("Metadata() = %+v", meta)
+	}
+}
+
+func TestSaveThumbnail(t *testing.T) {
+	content, err := os.ReadFile(writePNG(t, 200, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := NewUploader(t.TempDir(), newFileHeader(t, "photo.png", content), WithThumbnail(50, 50, "-thumb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(u.Path()); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(u.ThumbnailPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max(config.Width, config.Height) != 50 {
+		t.Errorf("thumbnail size = %dx%d, want max dimension 50", config.Width, config.Height)
+	}
+}
+
+func TestMimeLimit(t *testing.T) {
+	content, err := pngBytes(t, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		limit int
+		png   bool
+	}{
+		{0, true},
+		{512, true},
+		{1, false},
+	}
+
+	for _, tt := range tests {
+		u, err := NewUploader(t.TempDir(), newFileHeader(t, "image.png", content), WithMimeLimit(tt.limit))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mime, err := u.MIME()
+		if err != nil {
+			t.Fatalf("limit %d: %v", tt.limit, err)
+		}
+		// Tiny limit degrades to generic type
+		if (mime == "image/png") != tt.png || mime == "" {
+			t.Errorf("limit %d: MIME() = %q, png %v", tt.limit, mime, tt.png)
+		}
+	}
+}
+
+func TestValidateTotalSize(t *testing.T) {
+	files := []*multipart.FileHeader{
+		newFileHeader(t, "a.txt", bytes.Repeat([]byte("a"), 400)),
+		newFileHeader(t, "b.txt", bytes.Repeat([]byte("b"), 300)),
+		newFileHeader(t, "c.txt", bytes.Repeat([]byte("c"), 300)),
+		nil,
+	}
+
+	tests := []struct {
+		max   string
+		valid bool
+	}{
+		{"1KB", true},
+		{"1000B", true},
+		{"999B", false},
+	}
+
+	for _, tt := range tests {
+		valid, err := ValidateTotalSize(tt.max, files...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid != tt.valid {
+			t.Errorf("ValidateTotalSize(%q) = %v, want %v", tt.max, valid, tt.valid)
+		}
+	}
+
+	if _, err := ValidateTotalSize("invalid", files...); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
+
+func TestScanner(t *testing.T) {
+	errInfected := errors.New("infected")
+	scanner := func(r io.Reader) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		} else if bytes.Contains(content, []byte("bad")) {
+			return errInfected
+		}
+		return nil
+	}
+
+	tests := []struct {
+		content string
+		err     error
+	}{
+		{"good payload", nil},
+		{"bad payload", errInfected},
+	}
+
+	for _, tt := range tests {
+		u, err := NewUploader(t.TempDir(), newFileHeader(t, "file.txt", []byte(tt.content)), WithScanner(scanner))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := u.Save(); !errors.Is(err, tt.err) {
+			t.Errorf("%q: Save() = %v, want %v", tt.content, err, tt.err)
+		}
+
+		_, err = os.Stat(u.Path())
+		if saved := err == nil; saved != (tt.err == nil) {
+			t.Errorf("%q: file saved = %v", tt.content, saved)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	// Small file is buffered in memory and large file is stored in multipart temp file
+	for _, size := range []int{100 * 1024, 2 << 20} {
+		var calls []int64
+		var total int64
+		progress := func(written, n int64) {
+			calls = append(calls, written)
+			total = n
+		}
+
+		u, err := NewUploader(t.TempDir(), newFileHeader(t, "file.bin", bytes.Repeat([]byte("x"), size)), WithProgress(progress))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := u.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(calls) == 0 || calls[len(calls)-1] != int64(size) || total != int64(size) {
+			t.Errorf("size %d: progress calls = %v, total = %d", size, calls, total)
+		}
+		if !slices.IsSorted(calls) {
+			t.Errorf("size %d: progress not increasing: %v", size, calls)
+		}
+
+		info, err := os.Stat(u.Path())
+		if err != nil || info.Size() != int64(size) {
+			t.Errorf("size %d: saved file = %v, %v", size, info, err)
+		}
+	}
+}
+
+func TestValidateMimeAfterRead(t *testing.T) {
+	png, err := pngBytes(t, 8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Small file is buffered in memory and large file is stored in multipart temp file
+	for _, size := range []int{len(png), 2 << 20} {
+		content := append(slices.Clone(png), make([]byte, size-len(png))...)
+		file := newFileHeader(t, "image.png", content)
+
+		// Handler reads some bytes before validation
+		f, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := io.ReadFull(f, make([]byte, 16)); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := NewUploader(t.TempDir(), file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := u.ValidateMime("image/png"); err != nil || !ok {
+			t.Fatalf("size %d: ValidateMime = %v, %v", size, ok, err)
+		}
+		if err := u.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		saved, err := os.ReadFile(u.Path())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(saved, content) {
+			t.Errorf("size %d: saved file differs, %d bytes of %d", size, len(saved), len(content))
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	t.Cleanup(func() { rename = os.Rename })
+
+	content := []byte("moved content")
+	for _, crossDevice := range []bool{false, true} {
+		// Simulate rename failure across filesystems
+		if crossDevice {
+			rename = func(string, string) error { return errors.New("invalid cross-device link") }
+		}
+
+		u, err := NewUploader(t.TempDir(), newFileHeader(t, "file.txt", content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := u.Move(t.TempDir()); err == nil {
+			t.Error("moving not saved file returned no error")
+		}
+		if err := u.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		src, root := u.Path(), t.TempDir()
+		if err := u.Move(root); err != nil {
+			t.Fatalf("cross device %v: %v", crossDevice, err)
+		}
+
+		if !strings.HasPrefix(u.Path(), root) {
+			t.Errorf("cross device %v: Path() = %q, want under %q", crossDevice, u.Path(), root)
+		}
+		if saved, err := os.ReadFile(u.Path()); err != nil || !bytes.Equal(saved, content) {
+			t.Errorf("cross device %v: moved file = %q, %v", crossDevice, saved, err)
+		}
+		if _, err := os.Stat(src); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("cross device %v: source file not removed: %v", crossDevice, err)
+		}
+	}
+}
+
+func TestValidateImageBomb(t *testing.T) {
+	png, err := pngBytes(t, 10, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		valid   bool
+	}{
+		{"normal image", png, true},
+		{"bomb image", bombPNG(100_000, 100_000), false},
+		{"not image", []byte("plain text"), true},
+	}
+
+	for _, tt := range tests {
+		u, err := NewUploader(t.TempDir(), newFileHeader(t, "file.png", tt.content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid, err := u.ValidateImageBomb(1_000_000); err != nil || valid != tt.valid {
+			t.Errorf("%s: ValidateImageBomb = %v, %v, want %v", tt.name, valid, err, tt.valid)
+		}
+	}
+}
+
+func TestSaveMovesTempFile(t *testing.T) {
+	t.Cleanup(func() { rename = os.Rename })
+
+	content := bytes.Repeat([]byte("x"), 2<<20)
+	for _, crossDevice := range []bool{false, true} {
+		// Simulate rename failure across filesystems
+		if crossDevice {
+			rename = func(string, string) error { return errors.New("invalid cross-device link") }
+		}
+
+		// Large file is stored in multipart temp file
+		file := newFileHeader(t, "file.bin", content)
+		src, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		temp, ok := src.(*os.File)
+		if !ok {
+			t.Fatal("multipart file not stored in temp file")
+		}
+		src.Close()
+
+		u, err := NewUploader(t.TempDir(), file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := u.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		if saved, err := os.ReadFile(u.Path()); err != nil || !bytes.Equal(saved, content) {
+			t.Errorf("cross device %v: saved file differs: %v", crossDevice, err)
+		}
+		_, err = os.Stat(temp.Name())
+		if moved := errors.Is(err, os.ErrNotExist); moved == crossDevice {
+			t.Errorf("cross device %v: temp file moved = %v", crossDevice, moved)
+		}
+	}
+}